@@ -18,15 +18,27 @@ _g() {
     --classic
     --color
     --colorless
+    --compact-dirs
     --depth
+    --dir-summary
     --format
     --file-type
+    --flat
+    --recursive-flat
+    --follow-links
     --md
     --markdown
+    --ndjson
+    --one-file-system
+    --raw
+    --skip-fs
+    --stream
+    --yaml
     --table
     --table-style
     --term-width
     --theme
+    --tree-errors
     --tree-style
     --zero -0 -C -F -R -T -d -j -m -x
     --init
@@ -48,13 +60,18 @@ _g() {
     --checksum-algorithm
     --created
     --dereference
+    --extended
     --footer
+    --from-stdin
     --full-path
     --full-time
     --flags
     --gid
     --git
     --git-status
+    --git-count
+    --git-diffstat
+    --git-diffstat-staged
     --git-repo-branch
     --branch
     --git-repo-status
@@ -86,6 +103,7 @@ _g() {
     --smart-group
     --statistic
     --stdin
+    --stdin0
     --time
     --time-style
     --time-type
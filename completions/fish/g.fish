@@ -15,16 +15,29 @@ complete -c g -l byline -s 1 -d "print by line"
 complete -c g -l classic -d "enable classic mode"
 complete -c g -l color -d "set terminal color mode" -a "always auto never basic 256 24bit"
 complete -c g -l colorless -d "without color"
+complete -c g -l compact-dirs -d "merge chains of dirs containing only a single dir in tree"
 complete -c g -l depth -d "limit recursive/tree depth" -r -f
-complete -c g -l format -d "set output format" -a "across commas horizontal long single-column verbose vertical table markdown csv tsv json tree"
+complete -c g -l dir-summary -d "show the recursive file count, dir count and size of each dir in tree"
+complete -c g -l format -d "set output format" -a "across commas horizontal long single-column verbose vertical table markdown csv tsv json ndjson yaml tree"
 complete -c g -l flags -d "list file flags" -r -f
+complete -c g -l extended -s @ -d "list extended attributes and sizes"
 complete -c g -l file-type -d "do not append indicator to file types"
+complete -c g -l flat -d "list the entries of all the sub dirs together"
+complete -c g -l follow-links -d "descend into symlinked directories in tree and recursive listing"
 complete -c g -l md -d "output in markdown-table format"
+complete -c g -l ndjson -d "output in newline delimited json format"
+complete -c g -l one-file-system -d "do not descend into dirs on other filesystems"
+complete -c g -l raw -d "output typed values for json/csv/tsv"
+complete -c g -l skip-fs -d "do not descend into mountpoints of given filesystem types" -r
+complete -c g -l stream -d "read and print entries in batches without sorting"
+complete -c g -l yaml -d "output in yaml format"
 complete -c g -l markdown -d "output in markdown-table format"
+complete -c g -l recursive-flat -d "list the entries of all the sub dirs together"
 complete -c g -l table -d "output in table format"
 complete -c g -l table-style -d "set table style" -a "ascii unicode"
 complete -c g -l term-width -d "set screen width" -r -f
 complete -c g -l theme -d "apply theme" -a "(__fish_complete_path)"
+complete -c g -l tree-errors -d "where to report unreadable dirs in tree" -a "inline stderr both"
 complete -c g -l tree-style -d "set tree style" -a "ascii unicode rectangle"
 complete -c g -l zero -s 0 -d "end each output line with NUL"
 complete -c g -s C -d "list entries by columns"
@@ -56,10 +69,14 @@ complete -c g -l checksum-algorithm -d "checksum algorithm" -a "md5 sha1 sha224
 complete -c g -l created -d "created time"
 complete -c g -l dereference -d "dereference symbolic links"
 complete -c g -l footer -d "add a footer row"
+complete -c g -l from-stdin -d "list the paths from stdin in tree"
 complete -c g -l full-path -d "show full path"
 complete -c g -l full-time -d "like -all/l --time-style=full-iso" -a "default iso long-iso full-iso +FORMAT"
 complete -c g -l gid -d "show gid instead of groupname"
 complete -c g -l git -l git-status -d "show git status"
+complete -c g -l git-count -d "show the number of changed entries in dirs by git status"
+complete -c g -l git-diffstat -d "show the lines added and deleted against HEAD"
+complete -c g -l git-diffstat-staged -d "show the lines added and deleted in the index against HEAD"
 complete -c g -l git-repo-branch -l branch -d "list root of git-tree branch"
 complete -c g -l git-repo-status -l repo-status -d "list root of git-tree status"
 complete -c g -l group -d "show group"
@@ -86,6 +103,7 @@ complete -c g -l size-unit -l block-size -d "set size unit" -a "bit \"bit\" b \"
 complete -c g -l smart-group -d "only show group if different from owner"
 complete -c g -l statistic -d "show statistic info"
 complete -c g -l stdin -d "read path from stdin, split by newline"
+complete -c g -l stdin0 -d "list the NUL-separated paths from stdin in tree"
 complete -c g -l time -d "show time"
 complete -c g -l time-style -d "set time/date format" -r -f
 complete -c g -l time-type -d "set time type" -a "mod \"modified\" create access all birth"
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	contents "github.com/Equationzhao/g/internal/content"
+	"github.com/Equationzhao/g/internal/item"
+)
+
+// compactTree merges the chains of dirs which only contain a single dir into their deepest dir, see --compact-dirs.
+// infos[0] is the root of the tree, the others are collected by dive.
+// the deepest dir keeps its own columns, and the names of the merged dirs are prefixed to its name
+func compactTree(infos []*item.FileInfo, followLinks bool) []*item.FileInfo {
+	children := make(map[string][]*item.FileInfo, len(infos))
+	for _, info := range infos[1:] {
+		parent := string(info.Cache["parent"])
+		children[parent] = append(children[parent], info)
+	}
+	// parents are visited before their children
+	byLevel := slices.Clone(infos[1:])
+	slices.SortStableFunc(byLevel, func(a, b *item.FileInfo) int {
+		la, _ := strconv.Atoi(string(a.Cache["level"]))
+		lb, _ := strconv.Atoi(string(b.Cache["level"]))
+		return la - lb
+	})
+
+	merged := make(map[*item.FileInfo]struct{})
+	for _, head := range byLevel {
+		if _, ok := merged[head]; ok || !isTreeDir(head, followLinks) {
+			continue
+		}
+		deepest, prefix := head, ""
+		for {
+			c := children[deepest.FullPath]
+			if len(c) != 1 || !isTreeDir(c[0], followLinks) {
+				break
+			}
+			merged[deepest] = struct{}{}
+			prefix += deepest.Name() + string(filepath.Separator)
+			deepest = c[0]
+		}
+		if deepest != head {
+			deepest.Cache["parent"] = head.Cache["parent"]
+			deepest.Cache[contents.NamePrefixName] = []byte(prefix)
+		}
+	}
+	if len(merged) == 0 {
+		return infos
+	}
+
+	// reassign the levels from the root
+	levels := map[string]int{infos[0].FullPath: 0}
+	for _, info := range byLevel {
+		if _, ok := merged[info]; ok {
+			continue
+		}
+		level := levels[string(info.Cache["parent"])] + 1
+		levels[info.FullPath] = level
+		info.Cache["level"] = []byte(strconv.Itoa(level))
+	}
+	return slices.DeleteFunc(infos, func(info *item.FileInfo) bool {
+		_, ok := merged[info]
+		return ok
+	})
+}
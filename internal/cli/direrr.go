@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+
+	contents "github.com/Equationzhao/g/internal/content"
+	"github.com/Equationzhao/g/internal/item"
+)
+
+const (
+	dirErrorsInline = "inline"
+	dirErrorsStderr = "stderr"
+	dirErrorsBoth   = "both"
+)
+
+// attachDirErrors attaches the errors of reading dirs in the tree to their entries, see --tree-errors.
+// it returns the errors left to print to stderr
+func attachDirErrors(infos []*item.FileInfo, errs []error, mode string) []error {
+	if mode == dirErrorsStderr || len(errs) == 0 {
+		return errs
+	}
+	byPath := make(map[string]*item.FileInfo, len(infos))
+	for _, info := range infos {
+		byPath[filepath.Clean(info.FullPath)] = info
+	}
+	left := make([]error, 0, len(errs))
+	for _, err := range errs {
+		var pathErr *fs.PathError
+		if errors.As(err, &pathErr) {
+			if info, ok := byPath[filepath.Clean(pathErr.Path)]; ok {
+				info.Cache[contents.DirErrorName] = []byte(pathErr.Err.Error())
+				if mode == dirErrorsInline {
+					continue
+				}
+			}
+		}
+		left = append(left, err)
+	}
+	return left
+}
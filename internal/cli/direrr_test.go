@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	contents "github.com/Equationzhao/g/internal/content"
+	"github.com/Equationzhao/g/internal/item"
+)
+
+// fakeTree returns the entries of the tree under root, the names ending with '/' are dirs
+func fakeTree(t *testing.T, root string, names ...string) []*item.FileInfo {
+	t.Helper()
+	infos := make([]*item.FileInfo, 0, len(names))
+	for _, name := range names {
+		isDir := name[len(name)-1] == '/'
+		abs := filepath.Join(root, filepath.FromSlash(name))
+		info, err := item.NewFileInfoWithOption(
+			item.WithFileInfo(virtualInfo{name: filepath.Base(abs), isDir: isDir}), item.WithAbsPath(abs),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		info.Cache["parent"] = []byte(filepath.Dir(abs))
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+func TestAttachDirErrors(t *testing.T) {
+	root := filepath.FromSlash("/root")
+	denied := &fs.PathError{Op: "open", Path: filepath.Join(root, "denied"), Err: fs.ErrPermission}
+	outside := &fs.PathError{Op: "open", Path: filepath.Join(root, "gone"), Err: fs.ErrNotExist}
+	other := errors.New("walk interrupted")
+	errs := []error{denied, outside, other}
+
+	tests := []struct {
+		mode string
+		// wantLeft is the errors left to print to stderr
+		wantLeft []error
+		// wantAttached is whether the error is attached to the denied dir
+		wantAttached bool
+		// wantPruned is the names left by pruneTree
+		wantPruned []string
+	}{
+		{
+			mode:         dirErrorsInline,
+			wantLeft:     []error{outside, other},
+			wantAttached: true,
+			wantPruned:   []string{"denied", "full", "f"},
+		},
+		{
+			mode:         dirErrorsStderr,
+			wantLeft:     errs,
+			wantAttached: false,
+			// the unreadable dir without the error looks empty
+			wantPruned: []string{"full", "f"},
+		},
+		{
+			mode:         dirErrorsBoth,
+			wantLeft:     errs,
+			wantAttached: true,
+			wantPruned:   []string{"denied", "full", "f"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			infos := fakeTree(t, root, "denied/", "empty/", "full/", "full/f")
+			left := attachDirErrors(infos, slices.Clone(errs), tt.mode)
+			if !slices.Equal(left, tt.wantLeft) {
+				t.Errorf("attachDirErrors() left = %v, want %v", left, tt.wantLeft)
+			}
+			msg, attached := infos[0].Cache[contents.DirErrorName]
+			if attached != tt.wantAttached {
+				t.Errorf("attachDirErrors() attached = %v, want %v", attached, tt.wantAttached)
+			} else if attached && string(msg) != fs.ErrPermission.Error() {
+				t.Errorf("attachDirErrors() message = %q, want %q", msg, fs.ErrPermission.Error())
+			}
+			for _, info := range infos[1:] {
+				if _, ok := info.Cache[contents.DirErrorName]; ok {
+					t.Errorf("attachDirErrors() attached the error to %s", info.Name())
+				}
+			}
+
+			var pruned []string
+			for _, info := range pruneTree(infos, false) {
+				pruned = append(pruned, info.Name())
+			}
+			if !slices.Equal(pruned, tt.wantPruned) {
+				t.Errorf("pruneTree() = %v, want %v", pruned, tt.wantPruned)
+			}
+		})
+	}
+}
@@ -30,10 +30,24 @@ var displayFlag = []cli.Flag{
 			return nil
 		},
 	},
+	&cli.StringFlag{
+		Name:     "tree-errors",
+		Usage:    "where to report unreadable dirs in --tree [inline/stderr/both(default)], inline marks the dir like '[permission denied]'",
+		Value:    dirErrorsBoth,
+		Category: "DISPLAY",
+		Action: func(context *cli.Context, s string) error {
+			switch s {
+			case dirErrorsInline, dirErrorsStderr, dirErrorsBoth:
+				return nil
+			default:
+				return fmt.Errorf("invalid tree errors option: %s", s)
+			}
+		},
+	},
 	&cli.BoolFlag{
 		Name:               "T",
 		Aliases:            []string{"tree"},
-		Usage:              "recursively list in tree",
+		Usage:              "recursively list in tree, with --json/--yaml output nested nodes with children",
 		DisableDefaultText: true,
 		Category:           "DISPLAY",
 	},
@@ -72,6 +86,42 @@ var displayFlag = []cli.Flag{
 		Value:       -1,
 		Category:    "DISPLAY",
 	},
+	&cli.BoolFlag{
+		Name:               "flat",
+		Aliases:            []string{"recursive-flat"},
+		Usage:              "list the entries of all the sub dirs together with relative paths, sorted as a whole, eg: --flat -S -n 20 --file",
+		DisableDefaultText: true,
+		Category:           "DISPLAY",
+	},
+	&cli.BoolFlag{
+		Name:               "follow-links",
+		Usage:              "descend into symlinked directories in --tree and -R, loops are marked like '↻ (loop to ../x)'",
+		DisableDefaultText: true,
+		Category:           "DISPLAY",
+	},
+	&cli.BoolFlag{
+		Name:               "compact-dirs",
+		Usage:              "merge chains of dirs containing only a single dir in --tree, like 'src/main/java'",
+		DisableDefaultText: true,
+		Category:           "DISPLAY",
+	},
+	&cli.BoolFlag{
+		Name:               "dir-summary",
+		Usage:              "show the recursive file count, dir count and size of each dir in --tree, like '(12 files, 3 dirs, 1.2 MiB)'",
+		DisableDefaultText: true,
+		Category:           "DISPLAY",
+	},
+	&cli.BoolFlag{
+		Name:               "one-file-system",
+		Usage:              "do not descend into dirs on other filesystems in --tree and -R, mountpoints are annotated with the mount info",
+		DisableDefaultText: true,
+		Category:           "DISPLAY",
+	},
+	&cli.StringSliceFlag{
+		Name:     "skip-fs",
+		Usage:    "do not descend into mountpoints of given filesystem types in --tree and -R, eg: --skip-fs=nfs,fuse.sshfs",
+		Category: "DISPLAY",
+	},
 	&cli.BoolFlag{
 		Name:               "R",
 		Aliases:            []string{"recurse"},
@@ -170,7 +220,7 @@ var displayFlag = []cli.Flag{
 	&cli.BoolFlag{
 		Name:               "j",
 		Aliases:            []string{"json"},
-		Usage:              "output in json format",
+		Usage:              "output in json format, multiple paths and -R are output as an array of {path, entries, extra}",
 		DisableDefaultText: true,
 		Action: func(context *cli.Context, b bool) error {
 			if b {
@@ -186,6 +236,63 @@ var displayFlag = []cli.Flag{
 		},
 		Category: "DISPLAY",
 	},
+	&cli.BoolFlag{
+		Name:               "ndjson",
+		Usage:              "output in newline delimited json format, one entry per line",
+		DisableDefaultText: true,
+		Action: func(context *cli.Context, b bool) error {
+			if b {
+				if jp, ok := p.(*display.JsonPrinter); ok {
+					jp.NDJSON = true
+				} else {
+					p = display.NewNDJsonPrinter()
+				}
+			}
+
+			_ = context.Set("header", "0")
+			theme.SetClassic()
+			theme.ColorLevel = theme.None
+			return nil
+		},
+		Category: "DISPLAY",
+	},
+	&cli.BoolFlag{
+		Name:               "yaml",
+		Usage:              "output in yaml format, like --json",
+		DisableDefaultText: true,
+		Action: func(context *cli.Context, b bool) error {
+			if b {
+				if jp, ok := p.(*display.JsonPrinter); ok {
+					jp.YAML = true
+				} else {
+					p = display.NewYamlPrinter()
+				}
+			}
+
+			_ = context.Set("header", "0")
+			theme.SetClassic()
+			theme.ColorLevel = theme.None
+			return nil
+		},
+		Category: "DISPLAY",
+	},
+	&cli.BoolFlag{
+		Name:               "raw",
+		Aliases:            []string{"machine"},
+		Usage:              "output typed values for json/csv/tsv(bytes, RFC3339 times, split git status), implies --json if no format is set",
+		DisableDefaultText: true,
+		Action: func(context *cli.Context, b bool) error {
+			if b {
+				display.Raw = true
+				_ = context.Set("header", "0")
+				_ = context.Set("no-icon", "1")
+				theme.SetClassic()
+				theme.ColorLevel = theme.None
+			}
+			return nil
+		},
+		Category: "DISPLAY",
+	},
 	&cli.StringFlag{
 		Name:    "tb-style",
 		Aliases: []string{"table-style"},
@@ -277,7 +384,7 @@ var displayFlag = []cli.Flag{
 		Name:        "format",
 		DefaultText: "C",
 		Usage: `across  -x,  commas  -m, horizontal -x, long -l, single-column -1,
-	verbose -l, vertical -C, table -tb, markdown -md, csv -csv, tsv -tsv, json -j, tree -T`,
+	verbose -l, vertical -C, table -tb, markdown -md, csv -csv, tsv -tsv, json -j, ndjson, yaml, tree -T`,
 		Action: func(context *cli.Context, s string) error {
 			switch s {
 			case "across", "x", "horizontal":
@@ -344,6 +451,24 @@ var displayFlag = []cli.Flag{
 					theme.SetClassic()
 					theme.ColorLevel = theme.None
 				}
+			case "ndjson":
+				if jp, ok := p.(*display.JsonPrinter); ok {
+					jp.NDJSON = true
+				} else {
+					p = display.NewNDJsonPrinter()
+					_ = context.Set("classic", "1")
+					theme.SetClassic()
+					theme.ColorLevel = theme.None
+				}
+			case "yaml":
+				if jp, ok := p.(*display.JsonPrinter); ok {
+					jp.YAML = true
+				} else {
+					p = display.NewYamlPrinter()
+					_ = context.Set("classic", "1")
+					theme.SetClassic()
+					theme.ColorLevel = theme.None
+				}
 			case "tree", "T":
 				if _, ok := p.(*display.TreePrinter); !ok {
 					p = display.NewTreePrinter()
@@ -355,6 +480,13 @@ var displayFlag = []cli.Flag{
 		},
 		Category: "DISPLAY",
 	},
+	&cli.BoolFlag{
+		Name: "stream",
+		Usage: `read and print entries in batches without sorting(implies -U), for huge directories,
+	output in byline/zero/ndjson/csv/tsv format(default: byline), report progress to stderr when stdout is not a terminal`,
+		DisableDefaultText: true,
+		Category:           "DISPLAY",
+	},
 	&cli.StringFlag{
 		Name:  "theme",
 		Usage: "apply theme `path/to/theme`",
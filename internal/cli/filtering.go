@@ -3,14 +3,22 @@ package cli
 import (
 	"errors"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	contents "github.com/Equationzhao/g/internal/content"
 	"github.com/Equationzhao/g/internal/filter"
+	"github.com/Equationzhao/g/internal/item"
+	"github.com/Equationzhao/g/internal/util"
 	strftime "github.com/itchyny/timefmt-go"
 	"github.com/urfave/cli/v2"
 )
 
+// ignoreFilterFunc are the filters hiding dirs along with their contents,
+// other filters only apply to files in the tree with --prune
+var ignoreFilterFunc = map[*filter.ItemFilterFunc]bool{&filter.RemoveHidden: true}
+
 var filteringFlag = []cli.Flag{
 	&cli.UintFlag{
 		Name:        "n",
@@ -20,6 +28,11 @@ var filteringFlag = []cli.Flag{
 		DefaultText: "unlimited",
 		Category:    "FILTERING",
 	},
+	&cli.UintFlag{
+		Name:     "tree-limit",
+		Usage:    "in --tree, show at most NUM entries of each dir after sorting, the rest are summarized like '… 49,982 more entries (3.1 GiB)'",
+		Category: "FILTERING",
+	},
 	&cli.StringSliceFlag{
 		Name:    "I",
 		Aliases: []string{"ignore"},
@@ -31,6 +44,7 @@ var filteringFlag = []cli.Flag{
 					return err
 				}
 				itemFilterFunc = append(itemFilterFunc, &f)
+				ignoreFilterFunc[&f] = true
 			}
 			return nil
 		},
@@ -84,6 +98,18 @@ var filteringFlag = []cli.Flag{
 		},
 		Category: "FILTERING",
 	},
+	&cli.BoolFlag{
+		Name:               "prune",
+		DisableDefaultText: true,
+		Usage:              "in --tree, apply the filters to files only and remove dirs without matched files, eg: -T --prune -M '*.proto'",
+		Category:           "FILTERING",
+	},
+	&cli.BoolFlag{
+		Name:               "git-ignore",
+		DisableDefaultText: true,
+		Usage:              "hide git ignored file/dir",
+		Category:           "FILTERING",
+	},
 	&cli.StringSliceFlag{
 		Name:  "ext",
 		Usage: "show file which has target ext, eg: --ext=go,java",
@@ -143,6 +169,7 @@ var filteringFlag = []cli.Flag{
 		Action: func(context *cli.Context, b bool) error {
 			if b {
 				itemFilterFunc = append(itemFilterFunc, &filter.RemoveBackups)
+				ignoreFilterFunc[&filter.RemoveBackups] = true
 			}
 			return nil
 		},
@@ -246,4 +273,72 @@ var filteringFlag = []cli.Flag{
 			return errors.New("invalid time format")
 		},
 	},
+	&cli.StringFlag{
+		Name: "min-size",
+		Usage: `show items whose size is larger than or equal to the given size, eg: --min-size=10M,
+	K/M/G/T are powers of 1024 unless --si is set, dirs are only compared when --recursive-size is set`,
+		Category: "FILTERING",
+		Action: func(ctx *cli.Context, s string) error {
+			size, err := contents.ParseSizeWithSI(s, ctx.Bool("si"))
+			if err != nil {
+				ReturnCode = 2
+				return err
+			}
+			f := filter.MinSize(int64(size.Bytes), sizeOfFilter(ctx))
+			itemFilterFunc = append(itemFilterFunc, &f)
+			return nil
+		},
+	},
+	&cli.StringFlag{
+		Name:     "max-size",
+		Usage:    "show items whose size is smaller than or equal to the given size, eg: --max-size=1G, see --min-size",
+		Category: "FILTERING",
+		Action: func(ctx *cli.Context, s string) error {
+			size, err := contents.ParseSizeWithSI(s, ctx.Bool("si"))
+			if err != nil {
+				ReturnCode = 2
+				return err
+			}
+			f := filter.MaxSize(int64(size.Bytes), sizeOfFilter(ctx))
+			itemFilterFunc = append(itemFilterFunc, &f)
+			return nil
+		},
+	},
+	&cli.StringFlag{
+		Name: "where",
+		Usage: `show items matching the expression, eg: --where 'size > 10M && (ext == "go" || mime =~ "text/") && !hidden && mtime > -7d'
+	fields: name, path, ext, mime, git, size, mtime, atime, ctime, hidden, dir, file, link, exec`,
+		Category: "FILTERING",
+		Action: func(ctx *cli.Context, s string) error {
+			f, err := filter.Where(s, ctx.Bool("si"))
+			if err != nil {
+				ReturnCode = 2
+				return err
+			}
+			itemFilterFunc = append(itemFilterFunc, &f)
+			return nil
+		},
+	},
+}
+
+// sizeOfFilter returns the size compared by --min-size/--max-size,
+// dirs are compared by their recursive size when --recursive-size is set, otherwise they are kept
+func sizeOfFilter(ctx *cli.Context) filter.SizeFunc {
+	recursive, depth := ctx.Bool("recursive-size"), ctx.Int("depth")
+	return func(e *item.FileInfo) (int64, bool) {
+		if !e.IsDir() {
+			return e.Size(), true
+		}
+		if !recursive {
+			return 0, false
+		}
+		if r, ok := e.Cache[contents.RecursiveSizeName]; ok {
+			v, _ := strconv.ParseInt(string(r), 10, 64)
+			return v, true
+		}
+		// cache it for the size column
+		v := util.RecursivelySizeOf(e, depth)
+		e.Cache[contents.RecursiveSizeName] = []byte(strconv.FormatInt(v, 10))
+		return v, true
+	}
 }
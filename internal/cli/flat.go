@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"container/heap"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	contents "github.com/Equationzhao/g/internal/content"
+	"github.com/Equationzhao/g/internal/filter"
+	"github.com/Equationzhao/g/internal/item"
+	"github.com/Equationzhao/g/internal/util"
+)
+
+// flatCollector collects the entries for --flat,
+// only the top n entries are kept in a bounded heap if n > 0, so the others can be released during the walk
+type flatCollector struct {
+	m          sync.Mutex
+	entries    []*item.FileInfo
+	n          int
+	cmp        func(a, b *item.FileInfo) int
+	itemFilter *filter.ItemFilter
+}
+
+// AppendTo implements collector.
+// dive only checks dirs by the ignoring filters to descend into them, so they are checked by the item filter here
+func (c *flatCollector) AppendTo(info *item.FileInfo) {
+	if info.IsDir() && !c.itemFilter.Match(info) {
+		return
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.n <= 0 {
+		c.entries = append(c.entries, info)
+	} else if len(c.entries) < c.n {
+		heap.Push(c, info)
+	} else if c.cmp(info, c.entries[0]) < 0 {
+		// replace the last one of the top n
+		c.entries[0] = info
+		heap.Fix(c, 0)
+	}
+}
+
+// the heap keeps the last one of the top n entries at the top
+
+func (c *flatCollector) Len() int           { return len(c.entries) }
+func (c *flatCollector) Less(i, j int) bool { return c.cmp(c.entries[i], c.entries[j]) > 0 }
+func (c *flatCollector) Swap(i, j int)      { c.entries[i], c.entries[j] = c.entries[j], c.entries[i] }
+func (c *flatCollector) Push(x any)         { c.entries = append(c.entries, x.(*item.FileInfo)) }
+
+func (c *flatCollector) Pop() any {
+	last := c.entries[len(c.entries)-1]
+	c.entries = c.entries[:len(c.entries)-1]
+	return last
+}
+
+// listFlat visits the dir recursively by dive and returns the entries of all the sub dirs, see --flat.
+// the entries are sorted by cmp later, and their relative dirs are prefixed to the names
+func listFlat(
+	dir string, depth int, n uint, cmp func(a, b *item.FileInfo) int,
+	itemFilter, ignoreFilter *filter.ItemFilter, ancestors []ancestor, guard *fsGuard,
+) ([]*item.FileInfo, []error) {
+	if cmp == nil {
+		n = 0
+	}
+	c := &flatCollector{n: int(n), cmp: cmp, itemFilter: itemFilter}
+	if depth == 0 {
+		return nil, nil
+	}
+	wg := sync.WaitGroup{}
+	errSlice := util.NewSlice[error](10)
+	wg.Add(1)
+	go dive(dir, 1, depth, c, errSlice, &wg, itemFilter, ignoreFilter, ancestors, guard)
+	wg.Wait()
+	for _, info := range c.entries {
+		if rel, err := filepath.Rel(dir, filepath.Dir(info.FullPath)); err == nil && rel != "." {
+			info.Cache[contents.NamePrefixName] = []byte(rel + string(filepath.Separator))
+		}
+	}
+	return c.entries, slices.DeleteFunc(*errSlice.GetRaw(), func(err error) bool { return err == nil })
+}
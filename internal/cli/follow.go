@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	contents "github.com/Equationzhao/g/internal/content"
+	"github.com/Equationzhao/g/internal/item"
+	"github.com/Equationzhao/g/internal/osbased"
+	"github.com/Equationzhao/g/internal/util"
+)
+
+// ancestor is a dir on the way from the listed path to the current dir,
+// used by --follow-links to detect loops
+type ancestor struct {
+	path     string
+	dev, ino uint64
+	// real is the path with symlinks resolved, used when the device and inode number are not available
+	real string
+}
+
+func newAncestor(path string, info os.FileInfo) ancestor {
+	a := ancestor{path: path}
+	dev, ino, ok := osbased.FileID(info)
+	if ok {
+		a.dev, a.ino = dev, ino
+	} else if real, err := filepath.EvalSymlinks(path); err == nil {
+		a.real = real
+	} else {
+		a.real = path
+	}
+	return a
+}
+
+func (a ancestor) same(b ancestor) bool {
+	if a.real != "" || b.real != "" {
+		return a.real == b.real
+	}
+	return a.dev == b.dev && a.ino == b.ino
+}
+
+// withAncestor returns a new slice, as the ancestors are shared by goroutines of sibling dirs
+func withAncestor(ancestors []ancestor, a ancestor) []ancestor {
+	return append(slices.Clip(ancestors), a)
+}
+
+// followLink checks the symlink at path in dir,
+// returns whether it links to a dir, and the path relative to dir of the ancestor it loops to
+func followLink(dir, path string, ancestors []ancestor) (target ancestor, isDir bool, loopTo string) {
+	stat, err := os.Stat(path)
+	if err != nil || !stat.IsDir() {
+		return ancestor{}, false, ""
+	}
+	target = newAncestor(path, stat)
+	for _, a := range ancestors {
+		if a.same(target) {
+			if loopTo, err = filepath.Rel(dir, a.path); err != nil {
+				loopTo = a.path
+			}
+			return target, true, loopTo
+		}
+	}
+	return target, true, ""
+}
+
+// followSubDir reports whether -R should list the entry of dir, dirs and symlinks to dirs are listed unless they loop,
+// the ancestors of the entry are stored in ancestorsMap
+func followSubDir(dir string, info *item.FileInfo, ancestorsMap map[string][]ancestor) bool {
+	sub := filepath.Join(dir, info.Name())
+	var target ancestor
+	if info.IsDir() {
+		target = newAncestor(sub, info)
+	} else if util.IsSymLink(info) {
+		var isDir bool
+		var loopTo string
+		if target, isDir, loopTo = followLink(dir, sub, ancestorsMap[dir]); loopTo != "" {
+			info.Cache[contents.LoopName] = []byte(loopTo)
+			return false
+		} else if !isDir {
+			return false
+		}
+	} else {
+		return false
+	}
+	ancestorsMap[sub] = withAncestor(ancestorsMap[dir], target)
+	return true
+}
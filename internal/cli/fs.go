@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	contents "github.com/Equationzhao/g/internal/content"
+	"github.com/Equationzhao/g/internal/item"
+	"github.com/Equationzhao/g/internal/osbased"
+)
+
+// fsGuard stops --tree and -R at mountpoints, see --one-file-system and --skip-fs.
+// a dir is a mountpoint when its device differs from the dir containing it
+type fsGuard struct {
+	oneFS  bool
+	skipFS []string
+}
+
+// newFsGuard returns nil if neither --one-file-system nor --skip-fs is set
+func newFsGuard(oneFS bool, skipFS []string) *fsGuard {
+	if !oneFS && len(skipFS) == 0 {
+		return nil
+	}
+	return &fsGuard{oneFS: oneFS, skipFS: skipFS}
+}
+
+// devOf returns the device of the dir
+func (g *fsGuard) devOf(dir string) (uint64, bool) {
+	stat, err := os.Stat(dir)
+	if err != nil {
+		return 0, false
+	}
+	dev, _, ok := osbased.FileID(stat)
+	return dev, ok
+}
+
+// stop reports whether the dir at path shouldn't be descended,
+// parentDev is the device of the dir containing it.
+// the skipped mountpoint is annotated with its mount info
+func (g *fsGuard) stop(path string, target *item.FileInfo, parentDev uint64) bool {
+	dev, ok := g.devOf(path)
+	if !ok || dev == parentDev {
+		return false
+	}
+	mountpoint := path
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		mountpoint = real
+	}
+	fsType := contents.FsTypeOf(mountpoint)
+	if !g.oneFS && !slices.Contains(g.skipFS, fsType) {
+		return false
+	}
+	mounts := contents.MountsOn(mountpoint)
+	if mounts == "" && fsType != "" {
+		mounts = "[" + fsType + "]"
+	} else if mounts == "" {
+		mounts = "[mountpoint]"
+	}
+	target.Cache[contents.MountpointName] = []byte(mounts)
+	return true
+}
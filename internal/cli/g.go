@@ -13,7 +13,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/Equationzhao/g/internal/align"
 	"github.com/Equationzhao/g/internal/config"
 	contents "github.com/Equationzhao/g/internal/content"
 	"github.com/Equationzhao/g/internal/display"
@@ -57,6 +56,7 @@ var (
 	gitCommitEnabler = contents.NewGitCommitEnabler()
 	nameToDisplay    = contents.NewNameEnabler()
 	flagsEnabler     = contents.NewFlagsEnabler()
+	xattrEnabler     = contents.NewXattrEnabler()
 	depthLimitMap    map[string]int
 	hookOnce         = util.Once{}
 	duplicateDetect  = contents.NewDuplicateDetect()
@@ -159,11 +159,12 @@ func init() {
 	initVersionHelpFlags()
 }
 
-// dive
-// for generating file tree
+// dive visits the dir recursively to generate the file tree for --tree and --flat,
+// symlinked dirs are followed when ancestors is not nil, see --follow-links.
+// dirs are checked by dirFilter instead of itemFilter when it's not nil, see --prune and pruneTree
 func dive(
-	parent string, depth, limit int, infos *util.Slice[*item.FileInfo], errSlice *util.Slice[error],
-	wg *sync.WaitGroup, itemFilter *filter.ItemFilter,
+	parent string, depth, limit int, infos collector, errSlice *util.Slice[error],
+	wg *sync.WaitGroup, itemFilter, dirFilter *filter.ItemFilter, ancestors []ancestor, guard *fsGuard,
 ) {
 	defer wg.Done()
 	if limit > 0 && depth > limit {
@@ -174,6 +175,13 @@ func dive(
 		errSlice.AppendTo(err)
 		return
 	}
+	var parentDev uint64
+	if guard != nil {
+		var ok bool
+		if parentDev, ok = guard.devOf(parent); !ok {
+			guard = nil
+		}
+	}
 	for _, entry := range dir {
 		f, err := entry.Info()
 		if err != nil {
@@ -182,17 +190,158 @@ func dive(
 		}
 		nowAbs := filepath.Join(parent, f.Name())
 		info, _ := item.NewFileInfoWithOption(item.WithAbsPath(nowAbs), item.WithFileInfo(f))
+		isDir, next, loopTo := f.IsDir(), ancestors, ""
+		if isDir && ancestors != nil {
+			next = withAncestor(ancestors, newAncestor(nowAbs, f))
+		} else if ancestors != nil && util.IsSymLink(f) {
+			var target ancestor
+			target, isDir, loopTo = followLink(parent, nowAbs, ancestors)
+			if loopTo != "" {
+				isDir = false
+			} else if isDir {
+				next = withAncestor(ancestors, target)
+			}
+		}
 		// check filter
-		if !itemFilter.Match(info) {
+		if isDir && dirFilter != nil {
+			if !dirFilter.Match(info) {
+				continue
+			}
+		} else if !itemFilter.Match(info) {
 			continue
 		}
 		// store its parent and level/depth
 		info.Cache["parent"] = []byte(parent)
 		info.Cache["level"] = []byte(strconv.Itoa(depth))
-		infos.AppendTo(info)
-		if f.IsDir() {
+		if loopTo != "" {
+			info.Cache[contents.LoopName] = []byte(loopTo)
+		}
+		if isDir && (guard == nil || !guard.stop(nowAbs, info, parentDev)) {
 			wg.Add(1)
-			go dive(info.FullPath, depth+1, limit, infos, errSlice, wg, itemFilter)
+			go dive(info.FullPath, depth+1, limit, infos, errSlice, wg, itemFilter, dirFilter, next, guard)
+		}
+		infos.AppendTo(info)
+	}
+}
+
+// collector collects the entries visited by dive
+type collector interface {
+	AppendTo(info *item.FileInfo)
+}
+
+// pruneTree removes the dirs which don't contain any non-dir entry from the entries collected by dive, see --prune
+func pruneTree(infos []*item.FileInfo, followLinks bool) []*item.FileInfo {
+	parents := make(map[string]string, len(infos))
+	for _, info := range infos {
+		parents[info.FullPath] = string(info.Cache["parent"])
+	}
+	nonEmpty := make(map[string]struct{})
+	for _, info := range infos {
+		// the unreadable dirs are kept as files
+		if _, failed := info.Cache[contents.DirErrorName]; !failed && isTreeDir(info, followLinks) {
+			continue
+		}
+		for dir, ok := string(info.Cache["parent"]), true; ok; dir, ok = parents[dir] {
+			if _, visited := nonEmpty[dir]; visited {
+				break
+			}
+			nonEmpty[dir] = struct{}{}
+		}
+	}
+	return slices.DeleteFunc(infos, func(info *item.FileInfo) bool {
+		if _, failed := info.Cache[contents.DirErrorName]; failed || !isTreeDir(info, followLinks) {
+			return false
+		}
+		_, ok := nonEmpty[info.FullPath]
+		return !ok
+	})
+}
+
+// isTreeDir reports whether the entry is a dir or a symlinked dir descended by dive
+func isTreeDir(info *item.FileInfo, followLinks bool) bool {
+	if info.IsDir() {
+		return true
+	}
+	if _, loop := info.Cache[contents.LoopName]; loop || !followLinks || !util.IsSymLink(info) {
+		return false
+	}
+	stat, err := os.Stat(info.FullPath)
+	return err == nil && stat.IsDir()
+}
+
+// dotEntry returns the info of "."/".." in dir
+func dotEntry(dir, name string) (*item.FileInfo, error) {
+	// filepath.Join would clean the path, and the name would be the base of the dir
+	path := dir + string(filepath.Separator) + name
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return item.NewFileInfoWithOption(item.WithFileInfo(stat), item.WithAbsPath(filepath.Clean(path)))
+}
+
+// dirReader reads the entries of a dir in background
+type dirReader struct {
+	done  chan struct{}
+	infos []*item.FileInfo
+	errs  []error // errors of entries
+	err   error
+}
+
+// readDirAhead starts reading the dir, sem limits the number of dirs read at the same time, nil means no limit
+func readDirAhead(dir string, sem chan struct{}) *dirReader {
+	r := &dirReader{done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		if sem != nil {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+		}
+		d, err := os.ReadDir(dir)
+		if err != nil {
+			r.err = err
+			return
+		}
+		r.infos = make([]*item.FileInfo, 0, len(d))
+		for _, v := range d {
+			info, err := v.Info()
+			if err != nil {
+				r.errs = append(r.errs, err)
+				continue
+			}
+			fileInfo, err := item.NewFileInfoWithOption(
+				item.WithFileInfo(info), item.WithAbsPath(filepath.Join(dir, v.Name())),
+			)
+			if err != nil {
+				r.errs = append(r.errs, err)
+				continue
+			}
+			r.infos = append(r.infos, fileInfo)
+		}
+	}()
+	return r
+}
+
+// wait returns the entries after the dir is read
+func (r *dirReader) wait() ([]*item.FileInfo, []error, error) {
+	<-r.done
+	return r.infos, r.errs, r.err
+}
+
+// dereferenceInfos replaces the symlinks/aliases with their targets
+func dereferenceInfos(infos []*item.FileInfo) {
+	for i := range infos {
+		if util.IsSymLink(infos[i]) || osbased.IsMacOSAlias(infos[i].FullPath) {
+			symlinks, err := util.Evallinks(infos[i].FullPath)
+			if err != nil {
+				continue
+			}
+			info, err := os.Stat(symlinks)
+			if err != nil {
+				continue
+			}
+			infos[i].FileInfo = info
+			infos[i].FullPath = symlinks
 		}
 	}
 }
@@ -263,24 +412,37 @@ DISPLAY
    --classic                          enable classic mode(no colors or icons)
    --color WHEN/LEVEL                 set terminal colors [always|auto|never][basic|256|24bit](default: auto)
    --colorless, --no-color        	  without color
+   --compact-dirs                     merge chains of dirs containing only a single dir in --tree, like 'src/main/java'
    --depth NUM                        limit recursive/tree depth, negative -> infinity(default: infinity)
+   --dir-summary                      show the recursive file count, dir count and size of each dir in --tree, like '(12 files, 3 dirs, 1.2 MiB)'
    --format FORMAT                    across  -x,  commas  -m, horizontal -x, long -l, single-column -1,
-                                      verbose -l, vertical -C, table -tb, markdown -md, csv -csv, tsv -tsv, json -j, tree -T(default: C)
+                                      verbose -l, vertical -C, table -tb, markdown -md, csv -csv, tsv -tsv, json -j, ndjson, yaml, tree -T(default: C)
 
    --file-type                        like --classify, except do not append '*'
+   --flat, --recursive-flat           list the entries of all the sub dirs together with relative paths, sorted as a whole, eg: --flat -S -n 20 --file
+   --follow-links                     descend into symlinked directories in --tree and -R, loops are marked like '↻ (loop to ../x)'
    --md, --markdown                   output in markdown-table format
+   --ndjson                           output in newline delimited json format, one entry per line
+   --one-file-system                  do not descend into dirs on other filesystems in --tree and -R, mountpoints are annotated with the mount info
+   --raw, --machine                   output typed values for json/csv/tsv(bytes, RFC3339 times, split git status),
+                                      implies --json if no format is set
+   --skip-fs FSTYPE                   do not descend into mountpoints of given filesystem types in --tree and -R, eg: --skip-fs=nfs,fuse.sshfs
+   --stream                           read and print entries in batches without sorting(implies -U), for huge directories,
+                                      output in byline/zero/ndjson/csv/tsv format(default: byline), report progress to stderr when stdout is not a terminal
    --tb, --table                      output in table format
    --table-style STYLE                set table style [ascii(default)/unicode]
    --term-width COLS                  set screen width (default: auto)
    --theme path/to/theme              apply theme path/to/theme
+   --tree-errors WHERE                where to report unreadable dirs in --tree [inline/stderr/both(default)], inline marks the dir like '[permission denied]'
    --tree-style STYLE                 set tree style [ascii/unicode(default)/rectangle]
+   --yaml                             output in yaml format, like --json
    --zero, -0                         end each output line with NUL, not newline
    -C, --vertical                     list entries by columns(default)
    -F, --classify                     append indicator (one of */=@|) to entries
    -R, --recurse                      recurse into directories
-   -T, --tree                         recursively list in tree
+   -T, --tree                         recursively list in tree, with --json/--yaml output nested nodes with children
    -d, --directory,                   list directories themselves, not their contents
-   -j, --json                         output in json format
+   -j, --json                         output in json format, multiple paths and -R are output as an array of {path, entries, extra}
    -m, --comma                        fill width with a comma separated list of entries
    -x, --col, --across, --horizontal  list entries by lines instead of by columns
 
@@ -290,11 +452,18 @@ FILTERING
                                  the time will be parsed using format:
                                    MM-dd, MM-dd HH:mm, HH:mm, YYYY-MM-dd, YYYY-MM-dd HH:mm, and the format set by --time-style
    --ext value                   show file which has target ext, eg: --ext=go,java
-   --git-ignore                  hide git ignored file/dir [if git is installed]
+   --git-ignore                  hide git ignored file/dir
+   --max-size SIZE               show items whose size is smaller than or equal to the given size, eg: --max-size=1G, see --min-size
+   --min-size SIZE               show items whose size is larger than or equal to the given size, eg: --min-size=10M,
+                                 K/M/G/T are powers of 1024 unless --si is set, dirs are only compared when --recursive-size is set
    --no-dir, --file              do not show directory
    --no-ext value                show file which doesn't have target ext
    --only-mime value             only show file with given mime type
+   --prune                       in --tree, apply the filters to files only and remove dirs without matched files, eg: -T --prune -M '*.proto'
    --show-only-hidden, --hidden  show only hidden files(overridden by --show-hidden/-a/-A)
+   --tree-limit NUM              in --tree, show at most NUM entries of each dir after sorting, the rest are summarized like '… 49,982 more entries (3.1 GiB)'
+   --where EXPR                  show items matching the expression, eg: --where 'size > 10M && (ext == "go" || mime =~ "text/") && !hidden && mtime > -7d'
+                                 fields: name, path, ext, mime, git, size, mtime, atime, ctime, hidden, dir, file, link, exec
    -A, --almost-all              do not list implied . and ..
    -B, --ignore-backups          do not list implied entries ending with ~
    -D, --dir, --only-dir         show directory only
@@ -319,7 +488,8 @@ SORTING
    --sort SORT_FIELD                       sort by field, default: ascending and case-insensitive,
    available fields:                       nature(default),none(nosort),
                                            name,.name(sorts by name without a leading dot),
-                                           size,time,owner,group,extension,inode,width,mime.
+                                           size,time,owner,group,extension,inode,width,mime,
+                                           files(file count of dirs with --dir-summary, size also uses the summarized size of dirs).
                                            append '-descend' to sort descending
                                            field beginning with an Uppercase letter is case-sensitive
 
@@ -350,11 +520,15 @@ VIEW
    --extended, -@                          list each file's extended attributes and sizes in long listing
    --footer                                add a footer row
    --fp, --full-path, --fullpath           show full path
+   --from-stdin                            list the paths from stdin in tree, split by newline, eg: git ls-files | g --from-stdin
    --full-time                             like -all/l --time-style=full-iso
    --gid                                   show gid instead of groupname [sid in windows]
    --git, --git-status                     show git status [if git is installed]
+   --git-count                             show the number of changed entries in dirs by git status, like '3M 1?' [if git is installed]
+   --git-diffstat                          show the lines added and deleted in the worktree against HEAD, like '+12 −3', summed in dirs [if git is installed]
+   --git-diffstat-staged                   like --git-diffstat, but the index against HEAD [if git is installed]
    --git-repo-branch, --branch             list root of git-tree branch [if git is installed]
-   --git-repo-status, --repo-status        list root of git-tree status [if git is installed]
+   --git-repo-status, --repo-status        list root of git-tree status, with commits ahead/behind upstream, stash count, detached HEAD and merge/rebase/cherry-pick/bisect in progress [if git is installed]
    --group                                 show group
    --header, --title                       add a header row
    --hyperlink value                       attach hyperlink to filenames [auto|always|never](default: auto)
@@ -380,6 +554,7 @@ VIEW
    --smart-group                           only show group if it has a different name from owner
    --statistic                             show statistic info
    --stdin                                 read path from stdin, split by newline
+   --stdin0                                like --from-stdin, split by NUL, eg: find . -print0 | g --stdin0
    --time                                  show time
    --time-style TIME_TYPE                  time/date format with -l,
                                            valid TIME_TYPE are :
@@ -491,13 +666,28 @@ var logic = func(context *cli.Context) error {
 		nameToDisplay.SetClassify()
 		nameToDisplay.SetFileType()
 	}
-	if _, ok := p.(*display.JsonPrinter); ok {
+	if display.Raw {
+		switch p.(type) {
+		case *display.JsonPrinter, *display.CSVPrinter, *display.TSVPrinter:
+		default:
+			p = display.NewJsonPrinter()
+		}
+		nameToDisplay.SetJson()
+	} else if _, ok := p.(*display.JsonPrinter); ok {
 		nameToDisplay.SetJson()
 	}
 	git := context.Bool("git")
 	if git {
 		contentFunc = append(contentFunc, gitEnabler.Enable(r))
 	}
+	if context.Bool("git-count") {
+		git = true
+		contentFunc = append(contentFunc, gitEnabler.EnableCount(r))
+	}
+	if staged := context.Bool("git-diffstat-staged"); staged || context.Bool("git-diffstat") {
+		git = true
+		contentFunc = append(contentFunc, gitEnabler.EnableDiffStat(r, staged))
+	}
 
 	gitBranch := context.Bool("git-repo-branch")
 	if gitBranch {
@@ -510,12 +700,18 @@ var logic = func(context *cli.Context) error {
 
 	if context.Bool("git-detail") {
 		contentFunc = append(contentFunc, gitCommitEnabler.EnableHash(r), gitCommitEnabler.EnableAuthor(r), gitCommitEnabler.EnableAuthorDateWithTimeFormat(r, timeFormat))
+		// the log of each repository is shared by all the paths, including the subdirs of -R
+		defer gitCommitEnabler.Close()
 	}
 
 	if context.Bool("flags") {
 		contentFunc = append(contentFunc, flagsEnabler.Enable())
 	}
 
+	if context.Bool("extended") {
+		contentFunc = append(contentFunc, xattrEnabler.Enable(r))
+	}
+
 	if context.Bool("no-dereference") {
 		nameToDisplay.SetNoDeference()
 	}
@@ -565,13 +761,25 @@ var logic = func(context *cli.Context) error {
 	} else if context.Bool("fp") {
 		nameToDisplay.SetFullPath()
 	}
-	contentFunc = append(contentFunc, nameToDisplay.Enable(r))
+	nameIndex, nameOption := len(contentFunc), nameToDisplay.Enable(r)
+	contentFunc = append(contentFunc, nameOption)
 	itemFilter := filter.NewItemFilter(itemFilterFunc...)
+	// with --prune and --flat, only the ignoring filters decide whether to descend into dirs
+	ignoreFilter := filter.NewItemFilter()
+	for _, f := range itemFilterFunc {
+		if ignoreFilterFunc[f] {
+			ignoreFilter.AppendTo(f)
+		}
+	}
 
-	gitignore := context.Bool("git-ignore")
-	removeGitIgnore := new(filter.ItemFilterFunc)
-	if gitignore {
-		itemFilter.AppendTo(removeGitIgnore)
+	if context.Bool("git-ignore") {
+		removeGitIgnore := filter.RemoveGitIgnore()
+		itemFilter.AppendTo(&removeGitIgnore)
+		ignoreFilter.AppendTo(&removeGitIgnore)
+	}
+	var dirFilter *filter.ItemFilter
+	if context.Bool("prune") {
+		dirFilter = ignoreFilter
 	}
 	// if no path, use the current path
 	if len(path) == 0 {
@@ -584,10 +792,18 @@ var logic = func(context *cli.Context) error {
 	flagd := context.Bool("d")
 	// flag: if A is set
 	flagA := context.Bool("A")
-	flagR := context.Bool("R")
+	// --flat lists the entries of all the sub dirs together, instead of -R
+	flat := context.Bool("flat") && !context.Bool("tree")
+	flagR := context.Bool("R") && !flat
 	if flagR {
 		depthLimitMap = make(map[string]int)
 	}
+	followLinks := context.Bool("follow-links")
+	compactDirs := context.Bool("compact-dirs")
+	dirSummary := context.Bool("dir-summary")
+	treeErrors := context.String("tree-errors")
+	guard := newFsGuard(context.Bool("one-file-system"), context.StringSlice("skip-fs"))
+	ancestorsMap := make(map[string][]ancestor)
 	header := context.Bool("header")
 	footer := context.Bool("footer")
 	if context.Bool("statistic") {
@@ -595,6 +811,9 @@ var logic = func(context *cli.Context) error {
 	}
 
 	hyperlink := context.String("hyperlink")
+	if display.Raw {
+		hyperlink = "never"
+	}
 	switch hyperlink {
 	case "never":
 	case "always":
@@ -616,7 +835,10 @@ var logic = func(context *cli.Context) error {
 
 	flagSharp := context.Bool("#")
 	tree := context.Bool("tree")
-	if tree {
+	if jp, ok := p.(*display.JsonPrinter); ok && tree {
+		// nested json/yaml tree
+		jp.Tree = true
+	} else if tree {
 		if _, ok := p.(*display.TreePrinter); !ok {
 			p = display.NewTreePrinter()
 			if flagSharp {
@@ -633,6 +855,27 @@ var logic = func(context *cli.Context) error {
 	if n := context.Uint("n"); n > 0 && !tree {
 		contentFilter.LimitN = n
 	}
+	treeLimit := context.Uint("tree-limit")
+
+	// --stream doesn't work with tree, which needs all entries
+	stream := context.Bool("stream") && !tree && !flat
+	var streamLister *streamer
+	if stream {
+		setStreamPrinter()
+		_, isJson := p.(*display.JsonPrinter)
+		// the raw csv/tsv have their own header, and ndjson is neither padded nor has one
+		align := !display.Raw && !isJson
+		streamLister = &streamer{
+			itemFilter:  itemFilter,
+			dereference: context.Bool("dereference"),
+			dot:         !context.Bool("A"),
+			limit:       contentFilter.LimitN,
+			progress:    !util.IsTerminal(os.Stdout),
+			align:       align,
+			header:      header && align,
+		}
+		contentFilter.LimitN = 0
+	}
 
 	longestEachPart := make(map[string]int)
 	startDir, _ := os.Getwd()
@@ -656,28 +899,92 @@ var logic = func(context *cli.Context) error {
 		}
 		path = newPath
 	}
+	// --from-stdin lists the paths from stdin in a tree under their root
+	pathLists := make(map[string][]string)
+	if fromStdin, stdin0 := context.Bool("from-stdin"), context.Bool("stdin0"); fromStdin || stdin0 {
+		sep := byte('\n')
+		if stdin0 {
+			sep = 0
+		}
+		list, err := readPathList(os.Stdin, sep)
+		if err != nil {
+			return err
+		}
+		root := pathListRoot(list)
+		pathLists[root] = list
+		path = []string{root}
+	}
+
+	// the glob patterns in args are expanded and listed together, or in a tree like --from-stdin
+	path, globPattern, globMatches, err := globArgs(path, ignoreFilter)
+	if err != nil {
+		ReturnCode = 2
+		return err
+	}
+	// the dotfiles in the matches are already decided by globArgs
+	globFilter := itemFilter.Without(&filter.RemoveHidden)
+	var globRoot string
+	if tree && globPattern != "" {
+		globRoot = pathListRoot(globMatches)
+		pathLists[globRoot] = globMatches
+		path[slices.Index(path, globPattern)] = globRoot
+		globPattern = ""
+	}
 
 	// set sort func
 	if sort.Len() == 0 {
 		sort.AddOption(sorter.Default)
 	}
-	contentFilter.SetSortFunc(sort.Build())
+	if stream {
+		// entries are printed in directory order
+		contentFilter.SetSortFunc(nil)
+	} else {
+		contentFilter.SetSortFunc(sort.Build())
+	}
 	contentFilter.SetOptions(contentFunc...)
 	contentFilter.SetNoOutputOptions(noOutputFunc...)
+	// json output is a single document, see display.JsonPrinter.Multiple
+	jp, isJsonPrinter := p.(*display.JsonPrinter)
+	if isJsonPrinter && (len(path) > 1 || flagR) {
+		jp.Multiple = true
+	}
+	// the dirs in arguments are read ahead in parallel, and still printed in order
+	readers := make(map[string]*dirReader)
+	if len(path) > 1 && !tree && !stream && !flat && !flagd {
+		sem := make(chan struct{}, runtime.NumCPU())
+		for _, dir := range path {
+			abs, err := filepath.Abs(dir)
+			if err != nil {
+				continue
+			}
+			if stat, err := os.Stat(abs); err != nil || !stat.IsDir() {
+				continue
+			}
+			if _, ok := readers[abs]; !ok {
+				readers[abs] = readDirAhead(abs, sem)
+			}
+		}
+	}
 	for i := 0; i < len(path); i++ {
 		start := time.Now()
+		// the entries over --tree-limit by their dirs
+		var more map[string]*moreGroup
 
-		if len(path) > 1 {
+		if len(path) > 1 && !isJsonPrinter {
 			fmt.Println(r.DirPrompt(path[i]), ":")
 		}
 
-		if transformEnabled {
+		isGlob := globPattern != "" && path[i] == globPattern
+		if transformEnabled && !isGlob {
 			_, err := os.Stat(path[i])
 			if err != nil {
 				path[i] = pathbeautify.Transform(path[i])
 			}
 		}
 		originPath := path[i]
+		if isJsonPrinter {
+			jp.SetPath(originPath)
+		}
 
 		infos := make([]*item.FileInfo, 0, 20)
 
@@ -690,6 +997,9 @@ var logic = func(context *cli.Context) error {
 			continue
 		}
 		path[i] = absPath
+		if isGlob {
+			path[i] = startDir
+		}
 
 		stat, err := os.Stat(path[i])
 		if err != nil {
@@ -710,7 +1020,11 @@ var logic = func(context *cli.Context) error {
 				} else {
 					path[i] = newPath
 					stat, err = os.Stat(path[i])
-					fmt.Printf("%s:\n", path[i])
+					if isJsonPrinter {
+						jp.SetPath(newPath)
+					} else {
+						fmt.Printf("%s:\n", path[i])
+					}
 					if err != nil {
 						checkErr(err, "")
 						seriousErr = true
@@ -724,7 +1038,10 @@ var logic = func(context *cli.Context) error {
 				continue
 			}
 		}
-		if stat.IsDir() {
+		if isGlob {
+			infos = globInfos(globMatches)
+			isFile = true
+		} else if stat.IsDir() {
 			if flagd {
 				// when -d is set, treat dir as file
 				info, err := item.NewFileInfoWithOption(item.WithFileInfo(stat), item.WithPath(path[i]))
@@ -757,12 +1074,13 @@ var logic = func(context *cli.Context) error {
 				}
 			}()
 		}
-		if gitignore {
-			*removeGitIgnore = filter.RemoveGitIgnore(path[i])
-		}
 		if isFile {
 			// remove non-display items
-			infos = itemFilter.Filter(infos...)
+			if isGlob {
+				infos = globFilter.Filter(infos...)
+			} else {
+				infos = itemFilter.Filter(infos...)
+			}
 
 			if tree {
 				infos[0].Cache["level"] = []byte("0")
@@ -781,26 +1099,91 @@ var logic = func(context *cli.Context) error {
 				infos, info,
 			)
 			infos[0].Cache["level"] = []byte("0")
-			if depth >= 1 || depth < 0 {
+			if list, ok := pathLists[path[i]]; ok {
+				listFilter := itemFilter
+				if path[i] == globRoot {
+					listFilter = globFilter
+				}
+				infos = append(infos, pathListTree(path[i], list, depth, listFilter)...)
+			} else if depth >= 1 || depth < 0 {
 				wg := sync.WaitGroup{}
 				infoSlice := util.NewSlice[*item.FileInfo](10)
 				errSlice := util.NewSlice[error](10)
+				var ancestors []ancestor
+				if followLinks {
+					ancestors = []ancestor{newAncestor(path[i], info)}
+				}
 				wg.Add(1)
 				go dive(
-					path[i], 1, depth, infoSlice, errSlice, &wg, itemFilter,
+					path[i], 1, depth, infoSlice, errSlice, &wg, itemFilter, dirFilter, ancestors, guard,
 				)
 				wg.Wait()
 				infos = append(infos, *infoSlice.GetRaw()...)
-				for _, err := range *errSlice.GetRaw() {
-					if err != nil {
-						minorErr = true
-						checkErr(err, "")
-					}
+				errs := slices.DeleteFunc(*errSlice.GetRaw(), func(err error) bool { return err == nil })
+				if len(errs) > 0 {
+					minorErr = true
+				}
+				errs = attachDirErrors(infos, errs, treeErrors)
+				if dirFilter != nil {
+					infos = append(infos[:1], pruneTree(infos[1:], followLinks)...)
 				}
+				for _, err := range errs {
+					checkErr(err, "")
+				}
+			}
+			// the summary counts the dirs merged by --compact-dirs
+			if dirSummary {
+				summarizeTree(infos, depth)
+			}
+			if compactDirs {
+				infos = compactTree(infos, followLinks)
+			}
+		} else if flat {
+			var ancestors []ancestor
+			if followLinks {
+				ancestors = []ancestor{newAncestor(path[i], stat)}
+			}
+			entries, errs := listFlat(
+				path[i], depth, contentFilter.LimitN, contentFilter.SortFunc(), itemFilter, ignoreFilter, ancestors, guard,
+			)
+			infos = append(infos, entries...)
+			for _, err := range errs {
+				minorErr = true
+				checkErr(err, "")
+			}
+		} else if stream {
+			if git {
+				gitEnabler.Path = path[i]
+				gitEnabler.InitCache(path[i])
 			}
+			subDirs, errs, err := streamLister.stream(path[i])
+			if err != nil {
+				seriousErr = true
+				checkErr(err, originPath)
+				continue
+			}
+			for _, err := range errs {
+				minorErr = true
+				checkErr(err, "")
+			}
+			addTotalAndStatistic(nameToDisplay, start)
+			_ = hookOnce.Do(
+				func() error {
+					p.AddAfterPrint(hookPost...)
+					return nil
+				},
+			)
+			finishStream()
+			// only the sub dirs are left for -R
+			infos = subDirs
 		} else {
-			var d []os.DirEntry
-			d, err = os.ReadDir(path[i])
+			reader, ok := readers[path[i]]
+			if ok {
+				delete(readers, path[i])
+			} else {
+				reader = readDirAhead(path[i], nil)
+			}
+			d, errs, err := reader.wait()
 			if err != nil {
 				seriousErr = true
 				checkErr(err, originPath)
@@ -808,67 +1191,36 @@ var logic = func(context *cli.Context) error {
 			}
 
 			if !flagA && !tree { // if -A(almost-all) is not set, add the "."/".." info
-				err := os.Chdir(path[i])
+				FileInfoCurrent, err := dotEntry(path[i], ".")
 				if err != nil {
-					_, _ = fmt.Fprintln(os.Stderr, MakeErrorStr(err.Error()))
 					seriousErr = true
+					checkErr(err, ".")
 				} else {
-					FileInfoCurrent, err := item.NewFileInfo(".")
-					if err != nil {
-						seriousErr = true
-						checkErr(err, ".")
-					} else {
-						infos = append(infos, FileInfoCurrent)
-					}
-
-					FileInfoParent, err := item.NewFileInfo("..")
-					if err != nil {
-						minorErr = true
-						checkErr(err, "..")
-					} else {
-						infos = append(infos, FileInfoParent)
-					}
+					infos = append(infos, FileInfoCurrent)
 				}
-			}
 
-			for _, v := range d {
-				info, err := v.Info()
+				FileInfoParent, err := dotEntry(path[i], "..")
 				if err != nil {
 					minorErr = true
-					checkErr(err, "")
+					checkErr(err, "..")
 				} else {
-					info, err := item.NewFileInfoWithOption(
-						item.WithFileInfo(info), item.WithAbsPath(filepath.Join(path[i], v.Name())),
-					)
-					if err != nil {
-						checkErr(err, "")
-						seriousErr = true
-						continue
-					}
-					infos = append(infos, info)
+					infos = append(infos, FileInfoParent)
 				}
 			}
 
+			for _, err := range errs {
+				minorErr = true
+				checkErr(err, "")
+			}
+			infos = append(infos, d...)
+
 			// remove non-display items
 			infos = itemFilter.Filter(infos...)
 		}
 
 		// dereference
 		if dereference {
-			for i := range infos {
-				if util.IsSymLink(infos[i]) || osbased.IsMacOSAlias(infos[i].FullPath) {
-					symlinks, err := util.Evallinks(infos[i].FullPath)
-					if err != nil {
-						continue
-					}
-					info, err := os.Stat(symlinks)
-					if err != nil {
-						continue
-					}
-					infos[i].FileInfo = info
-					infos[i].FullPath = symlinks
-				}
-			}
+			dereferenceInfos(infos)
 		}
 
 		// if -R is set, add sub dir, insert into path[i+1]
@@ -880,14 +1232,31 @@ var logic = func(context *cli.Context) error {
 				depthLimitMap[path[i]] = depth
 				dep = depth
 			}
+			if followLinks {
+				if _, ok := ancestorsMap[path[i]]; !ok {
+					ancestorsMap[path[i]] = []ancestor{newAncestor(path[i], stat)}
+				}
+			}
+			var parentDev uint64
+			var parentOK bool
+			if guard != nil {
+				parentDev, parentOK = guard.devOf(path[i])
+			}
 			if dep >= 2 || dep <= -1 {
 				var j int
 				for _, info := range infos {
-					if info.IsDir() {
-						if info.Name() == "." || info.Name() == ".." {
-							continue
-						}
-						abs := filepath.Join(path[i], info.Name())
+					if info.Name() == "." || info.Name() == ".." {
+						continue
+					}
+					isDir := info.IsDir()
+					if followLinks {
+						isDir = followSubDir(path[i], info, ancestorsMap)
+					}
+					abs := filepath.Join(path[i], info.Name())
+					if isDir && parentOK && guard.stop(abs, info, parentDev) {
+						continue
+					}
+					if isDir {
 						newPath, err := filepath.Rel(startDir, abs)
 						if err == nil {
 							// if the path is relative, use it
@@ -902,17 +1271,36 @@ var logic = func(context *cli.Context) error {
 			}
 		}
 
+		if stream && !isFile {
+			goto clean
+		}
+
 	final:
 		if git {
 			repo := path[i]
-			if isFile {
+			if isFile && !isGlob {
 				repo = filepath.Dir(path[i])
 			}
 			gitEnabler.Path = repo
 			gitEnabler.InitCache(repo)
 		}
 
+		// the entries over --tree-limit are cut before their columns are computed
+		if treeLimit > 0 && tree && !isFile {
+			if sortFunc := contentFilter.SortFunc(); sortFunc != nil {
+				slices.SortFunc(infos, sortFunc)
+			}
+			infos, more = limitTree(infos, int(treeLimit))
+		}
 		contentFilter.GetDisplayItems(&infos)
+		if _, ok := pathLists[path[i]]; ok && tree {
+			_, isJson := p.(*display.JsonPrinter)
+			renderVirtual(infos, nameOption, nameIndex, isJson)
+		}
+		if len(more) != 0 {
+			_, isJson := p.(*display.JsonPrinter)
+			infos = addMoreEntries(infos, more, isJson)
+		}
 
 		if len(infos) == 0 {
 			goto clean
@@ -938,37 +1326,8 @@ var logic = func(context *cli.Context) error {
 				longestEachPart[s] = 0
 			}
 
-			if _, ok := p.(*display.JsonPrinter); !ok {
-				for _, it := range infos {
-					for _, part := range allPart {
-						content, ok := it.Get(part)
-						if ok && part != contents.NameName {
-							l := display.WidthNoHyperLinkLen(content.String())
-							if l > longestEachPart[part] {
-								longestEachPart[part] = l
-							}
-						}
-					}
-				}
-
-				// expand the length of each part using the scan result
-				for _, it := range infos {
-					for _, part := range allPart {
-						if part != contents.NameName {
-							content, _ := it.Get(part)
-							l := display.WidthNoHyperLinkLen(content.String())
-							if l < longestEachPart[part] {
-								expand := content.SetPrefix
-								if align.IsLeft(part) {
-									expand = content.SetSuffix
-								}
-								// expand
-								expand(strings.Repeat(" ", longestEachPart[part]-l))
-								it.Set(part, content)
-							}
-						}
-					}
-				}
+			if _, ok := p.(*display.JsonPrinter); !ok && !display.Raw {
+				display.AlignColumns(infos, allPart, longestEachPart)
 			}
 
 			_ = hookOnce.Do(
@@ -1005,14 +1364,15 @@ var logic = func(context *cli.Context) error {
 
 	clean:
 		if i != len(path)-1 {
-			fmt.Print("\n\n")
-			// switch back to start dir
-			if err = os.Chdir(startDir); err != nil {
-				seriousErr = true
+			if !isJsonPrinter {
+				fmt.Print("\n\n")
 			}
 			sizeEnabler.Reset()
 		}
 	}
+	if isJsonPrinter {
+		jp.Close()
+	}
 	wgUpdateIndex.Wait()
 
 	if seriousErr {
@@ -1083,7 +1443,18 @@ func addTotalAndStatistic(nameToDisplay *contents.Name, start time.Time) {
 			jp.Extra = make([]any, 0, 2)
 		}
 
-		if total, ok := sizeEnabler.Total(); ok {
+		if total, ok := sizeEnabler.Total(); ok && display.Raw {
+			// csv/tsv have no room for the total, json gets it in bytes
+			if isJsonPrinter {
+				jp.Extra = append(
+					jp.Extra, struct {
+						Total int64 `json:"total"`
+					}{
+						Total: total,
+					},
+				)
+			}
+		} else if ok {
 			s, unit := sizeEnabler.Size2String(total)
 			s = r.Size(s, contents.Convert2SizeString(unit))
 
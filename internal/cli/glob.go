@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	contents "github.com/Equationzhao/g/internal/content"
+	"github.com/Equationzhao/g/internal/filter"
+	"github.com/Equationzhao/g/internal/item"
+	"github.com/Equationzhao/g/internal/util"
+)
+
+// globArgs expands the args with glob metacharacters if no file has the literal name, see util.Glob.
+// the matches of all the patterns are listed together in place of the first pattern,
+// and the patterns without matches are left as they are.
+// the candidates are checked by ignoreFilter, so the dotfiles are only matched with -a or by the parts starting with '.',
+// and the dirs hidden or ignored by git with --git-ignore are not descended
+func globArgs(args []string, ignoreFilter *filter.ItemFilter) (path []string, pattern string, matches []string, err error) {
+	// the parts like '.git*' name the dotfiles explicitly, like the shells do
+	dotFilter := ignoreFilter.Without(&filter.RemoveHidden)
+	keep := func(p, part string) bool {
+		stat, err := os.Lstat(p)
+		if err != nil {
+			return false
+		}
+		info, err := item.NewFileInfoWithOption(item.WithFileInfo(stat), item.WithPath(p))
+		if err != nil {
+			return false
+		}
+		if strings.HasPrefix(part, ".") {
+			return dotFilter.Match(info)
+		}
+		return ignoreFilter.Match(info)
+	}
+	var patterns []string
+	at := -1
+	for _, arg := range args {
+		if !util.HasGlobMeta(arg) {
+			path = append(path, arg)
+			continue
+		}
+		if _, err := os.Lstat(arg); err == nil {
+			path = append(path, arg)
+			continue
+		}
+		m, err := util.Glob(arg, keep)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		if len(m) == 0 {
+			path = append(path, arg)
+			continue
+		}
+		if len(patterns) == 0 {
+			// the matches are listed here, named by the patterns joined below
+			at = len(path)
+			path = append(path, "")
+		}
+		patterns = append(patterns, arg)
+		matches = append(matches, m...)
+	}
+	if len(patterns) == 0 {
+		return path, "", nil, nil
+	}
+	slices.Sort(matches)
+	matches = slices.Compact(matches)
+	pattern = strings.Join(patterns, " ")
+	path[at] = pattern
+	return path, pattern, matches, nil
+}
+
+// globInfos returns the entries of the matches, named by their paths relative to the working dir
+func globInfos(matches []string) []*item.FileInfo {
+	infos := make([]*item.FileInfo, 0, len(matches))
+	for _, m := range matches {
+		stat, err := os.Stat(m)
+		if err != nil {
+			if stat, err = os.Lstat(m); err != nil {
+				continue
+			}
+		}
+		info, err := item.NewFileInfoWithOption(item.WithFileInfo(stat), item.WithPath(m))
+		if err != nil {
+			continue
+		}
+		if dir := filepath.Dir(m); dir != "." {
+			info.Cache[contents.NamePrefixName] = []byte(dir + string(filepath.Separator))
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Equationzhao/g/internal/filter"
+	"github.com/Equationzhao/g/internal/item"
+)
+
+func TestGlobArgs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"main.go", "ignored.go", ".gitignore", ".ignored-dot", ".hidden/h.go"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+
+	// removeIgnored stands for --git-ignore
+	var removeIgnored filter.ItemFilterFunc = func(e *item.FileInfo) bool {
+		return !strings.Contains(e.Name(), "ignored")
+	}
+	ignoreFilter := filter.NewItemFilter(&filter.RemoveHidden, &removeIgnored)
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantPath    []string
+		wantMatches []string
+	}{
+		{
+			name:        "wildcard",
+			args:        []string{"*.go"},
+			wantPath:    []string{"*.go"},
+			wantMatches: []string{"main.go"},
+		},
+		{
+			name:        "dotfiles named explicitly",
+			args:        []string{".git*"},
+			wantPath:    []string{".git*"},
+			wantMatches: []string{".gitignore"},
+		},
+		{
+			name:        "dotfiles named explicitly are still checked by --git-ignore",
+			args:        []string{".*"},
+			wantPath:    []string{".*"},
+			wantMatches: []string{".gitignore", ".hidden"},
+		},
+		{
+			name:        "hidden dirs are not descended",
+			args:        []string{"**/*.go"},
+			wantPath:    []string{"**/*.go"},
+			wantMatches: []string{"main.go"},
+		},
+		{
+			name:        "hidden dir named literally",
+			args:        []string{".hidden/*.go"},
+			wantPath:    []string{".hidden/*.go"},
+			wantMatches: []string{filepath.Join(".hidden", "h.go")},
+		},
+		{
+			name:        "patterns are merged",
+			args:        []string{"a", "*.go", "b", ".git*", "none*"},
+			wantPath:    []string{"a", "*.go .git*", "b", "none*"},
+			wantMatches: []string{".gitignore", "main.go"},
+		},
+		{
+			name:     "no pattern",
+			args:     []string{"main.go", "."},
+			wantPath: []string{"main.go", "."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, pattern, matches, err := globArgs(tt.args, ignoreFilter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(path, tt.wantPath) {
+				t.Errorf("globArgs() path = %q, want %q", path, tt.wantPath)
+			}
+			if !slices.Equal(matches, tt.wantMatches) {
+				t.Errorf("globArgs() matches = %q, want %q", matches, tt.wantMatches)
+			}
+			if len(tt.wantMatches) != 0 && !slices.Contains(path, pattern) {
+				t.Errorf("globArgs() pattern %q is not in path %q", pattern, path)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	contents "github.com/Equationzhao/g/internal/content"
+	"github.com/Equationzhao/g/internal/display"
+	"github.com/Equationzhao/g/internal/filter"
+	"github.com/Equationzhao/g/internal/item"
+	"github.com/Equationzhao/g/internal/osbased"
+)
+
+// virtualInfo is the os.FileInfo of the listed paths not existing on disk, see --from-stdin
+type virtualInfo struct {
+	name  string
+	isDir bool
+}
+
+func (v virtualInfo) Name() string       { return v.name }
+func (v virtualInfo) Size() int64        { return 0 }
+func (v virtualInfo) ModTime() time.Time { return time.Time{} }
+func (v virtualInfo) IsDir() bool        { return v.isDir }
+func (v virtualInfo) Sys() any           { return osbased.ZeroSys() }
+
+func (v virtualInfo) Mode() os.FileMode {
+	if v.isDir {
+		return os.ModeDir
+	}
+	return 0
+}
+
+// readPathList reads the paths split by sep, the empty ones are skipped
+func readPathList(r io.Reader, sep byte) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	scanner.Split(func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if i := bytes.IndexByte(data, sep); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	})
+	var paths []string
+	for scanner.Scan() {
+		if p := strings.TrimSuffix(scanner.Text(), "\r"); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths, scanner.Err()
+}
+
+// pathListRoot returns the root of the tree of the paths,
+// which is the working dir if all the paths are in it, or their common ancestor
+func pathListRoot(paths []string) string {
+	root, _ := os.Getwd()
+	inRoot := func(abs string) bool {
+		rel, err := filepath.Rel(root, abs)
+		return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+	}
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			continue
+		}
+		for !inRoot(abs) {
+			parent := filepath.Dir(root)
+			if parent == root {
+				break
+			}
+			root = parent
+		}
+	}
+	return root
+}
+
+// pathListTree returns the entries of the tree of the paths under root, see --from-stdin.
+// the dirs in the paths are added as needed, and the paths not existing on disk only have names.
+// the paths are skipped if they or their dirs are filtered out by itemFilter.
+// the paths deeper than depth are cut at depth if depth is not negative
+func pathListTree(root string, paths []string, depth int, itemFilter *filter.ItemFilter) []*item.FileInfo {
+	var infos []*item.FileInfo
+	entries := map[string]*item.FileInfo{}
+	matched := map[string]bool{}
+	// match reports whether the path and its dirs under root are kept by itemFilter
+	var match func(abs string) bool
+	match = func(abs string) bool {
+		if abs == root || filepath.Dir(abs) == abs {
+			return true
+		}
+		if m, ok := matched[abs]; ok {
+			return m
+		}
+		m := match(filepath.Dir(abs))
+		if m {
+			if stat, err := os.Lstat(abs); err == nil {
+				info, _ := item.NewFileInfoWithOption(item.WithFileInfo(stat), item.WithAbsPath(abs))
+				m = itemFilter.Match(info)
+			}
+		}
+		matched[abs] = m
+		return m
+	}
+	// add returns the entry of the path, the parent dirs are added first
+	var add func(abs string, isDir bool) *item.FileInfo
+	add = func(abs string, isDir bool) *item.FileInfo {
+		if info, ok := entries[abs]; ok {
+			if v, virtual := info.FileInfo.(virtualInfo); virtual && isDir && !v.isDir {
+				v.isDir = true
+				info.FileInfo = v
+			}
+			return info
+		}
+		parent := filepath.Dir(abs)
+		level := 1
+		if parent != root {
+			level, _ = strconv.Atoi(string(add(parent, true).Cache["level"]))
+			level++
+		}
+		stat, err := os.Lstat(abs)
+		if err != nil {
+			stat = virtualInfo{name: filepath.Base(abs), isDir: isDir}
+		}
+		info, _ := item.NewFileInfoWithOption(item.WithFileInfo(stat), item.WithAbsPath(abs))
+		if err != nil {
+			info.Cache[contents.VirtualName] = nil
+		}
+		info.Cache["parent"] = []byte(parent)
+		info.Cache["level"] = []byte(strconv.Itoa(level))
+		entries[abs] = info
+		infos = append(infos, info)
+		return info
+	}
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil || abs == root {
+			continue
+		}
+		isDir := strings.HasSuffix(p, "/")
+		if depth >= 0 {
+			rel, err := filepath.Rel(root, abs)
+			if err != nil {
+				continue
+			}
+			parts := strings.Split(rel, string(filepath.Separator))
+			if len(parts) > depth {
+				if depth == 0 {
+					continue
+				}
+				abs, isDir = filepath.Join(root, filepath.Join(parts[:depth]...)), true
+			}
+		}
+		if _, ok := entries[abs]; ok || !match(abs) {
+			continue
+		}
+		add(abs, isDir)
+	}
+	return infos
+}
+
+// renderVirtual renders the names of the entries without metadata, other columns are left empty,
+// and they are marked with '"virtual": true' in json
+func renderVirtual(infos []*item.FileInfo, name contents.ContentOption, nameNo int, json bool) {
+	var keys []item.Item
+	var names []string
+	for _, info := range infos {
+		if _, ok := info.Cache[contents.VirtualName]; !ok {
+			names = info.KeysByOrder()
+			for _, k := range names {
+				v, _ := info.Get(k)
+				keys = append(keys, v)
+			}
+			break
+		}
+	}
+	for _, info := range infos {
+		if _, ok := info.Cache[contents.VirtualName]; !ok {
+			continue
+		}
+		for i, k := range names {
+			if k != contents.NameName {
+				info.Set(k, &display.ItemContent{Content: display.StringContent(""), No: keys[i].NO()})
+			}
+		}
+		s, funcName := name(info)
+		info.Set(funcName, &display.ItemContent{Content: display.StringContent(s), No: nameNo})
+		if json {
+			info.Set("virtual", &display.ItemContent{Content: display.StringContent("true"), No: len(names)})
+			info.SetJson("virtual", []byte("true"))
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	contents "github.com/Equationzhao/g/internal/content"
+	"github.com/Equationzhao/g/internal/filter"
+)
+
+func TestReadPathList(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		sep   byte
+		want  []string
+	}{
+		{name: "newline", input: "a\nb c\r\n\nd/\n", sep: '\n', want: []string{"a", "b c", "d/"}},
+		{name: "no trailing separator", input: "a\nb", sep: '\n', want: []string{"a", "b"}},
+		{name: "nul", input: "a\nb\x00\x00c\x00", sep: 0, want: []string{"a\nb", "c"}},
+		{name: "empty", input: "", sep: '\n', want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readPathList(strings.NewReader(tt.input), tt.sep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("readPathList() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathListRoot(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd := filepath.Join(dir, "wd")
+	if err = os.Mkdir(wd, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(wd)
+
+	tests := []struct {
+		name  string
+		paths []string
+		want  string
+	}{
+		{name: "in working dir", paths: []string{"a", "b/c"}, want: wd},
+		{name: "sibling", paths: []string{"a", "../other/b"}, want: dir},
+		{name: "absolute", paths: []string{filepath.Join(dir, "x")}, want: dir},
+		{name: "none", paths: nil, want: wd},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pathListRoot(tt.paths); got != tt.want {
+				t.Errorf("pathListRoot() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathListTree(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{".gitignore", ".github/workflows/go.yml", "README.md", "sub/deep/f"} {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	paths := []string{".gitignore", ".github/workflows/go.yml", "README.md", "sub/deep/f", "missing/x"}
+	for i, p := range paths {
+		paths[i] = filepath.Join(root, filepath.FromSlash(p))
+	}
+
+	tests := []struct {
+		name   string
+		depth  int
+		filter *filter.ItemFilter
+		// want is the entries like 'path level', the virtual ones end with '*'
+		want []string
+	}{
+		{
+			name:   "all",
+			depth:  -1,
+			filter: filter.NewItemFilter(),
+			want: []string{
+				".gitignore 1", ".github 1", ".github/workflows 2", ".github/workflows/go.yml 3", "README.md 1",
+				"sub 1", "sub/deep 2", "sub/deep/f 3", "missing* 1", "missing/x* 2",
+			},
+		},
+		{
+			name:   "hidden dirs are filtered",
+			depth:  -1,
+			filter: filter.NewItemFilter(&filter.RemoveHidden),
+			want:   []string{"README.md 1", "sub 1", "sub/deep 2", "sub/deep/f 3", "missing* 1", "missing/x* 2"},
+		},
+		{
+			name:   "depth",
+			depth:  1,
+			filter: filter.NewItemFilter(&filter.RemoveHidden),
+			want:   []string{"README.md 1", "sub 1", "missing* 1"},
+		},
+		{
+			name:   "depth 0",
+			depth:  0,
+			filter: filter.NewItemFilter(),
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, info := range pathListTree(root, paths, tt.depth, tt.filter) {
+				rel, err := filepath.Rel(root, info.FullPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				entry := filepath.ToSlash(rel)
+				if _, virtual := info.Cache[contents.VirtualName]; virtual {
+					entry += "*"
+				}
+				got = append(got, entry+" "+string(info.Cache["level"]))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("pathListTree() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
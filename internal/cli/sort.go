@@ -18,7 +18,8 @@ var sortingFlags = []cli.Flag{
 	available fields: 	
 	   nature(default),none(nosort),
 	   name,.name(sorts by name without a leading dot),	
-	   size,time,owner,group,extension,inode,width,mime. 	
+	   size,time,owner,group,extension,inode,width,mime,
+	   files(file count of dirs with --dir-summary, size also uses the summarized size of dirs). 	
 	   following '-descend' to sort descending`,
 		Action: func(context *cli.Context, slice []string) error {
 			if slices.ContainsFunc(slice, func(s string) bool {
@@ -49,17 +50,25 @@ var sortingFlags = []cli.Flag{
 				case ".Name-descend":
 					sort.AddOption(sorter.ByNameWithoutALeadingDotCaseSensitiveDescend)
 				case "size-descend", "S", "sizesort":
-					if context.Bool("recursive-size") {
+					if context.Bool("dir-summary") {
+						sort.AddOption(sorter.ByDirSizeDescend)
+					} else if context.Bool("recursive-size") {
 						sort.AddOption(sorter.ByRecursiveSizeDescend(context.Int("depth")))
 					} else {
 						sort.AddOption(sorter.BySizeDescend)
 					}
 				case "size":
-					if context.Bool("recursive-size") {
+					if context.Bool("dir-summary") {
+						sort.AddOption(sorter.ByDirSizeAscend)
+					} else if context.Bool("recursive-size") {
 						sort.AddOption(sorter.ByRecursiveSizeAscend(context.Int("depth")))
 					} else {
 						sort.AddOption(sorter.BySizeAscend)
 					}
+				case "files-descend":
+					sort.AddOption(sorter.ByFileCountDescend)
+				case "files":
+					sort.AddOption(sorter.ByFileCountAscend)
 				case "time-descend":
 					sort.AddOption(sorter.ByTimeDescend(timeType[0]))
 				case "time":
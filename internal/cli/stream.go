@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Equationzhao/g/internal/display"
+	"github.com/Equationzhao/g/internal/filter"
+	"github.com/Equationzhao/g/internal/item"
+)
+
+// streamBatchSize is the number of entries read, computed and printed at a time by --stream
+const streamBatchSize = 1024
+
+// streamer lists a dir in batches without sorting, so that the output starts immediately
+// and the memory usage doesn't grow with the size of the dir
+type streamer struct {
+	itemFilter  *filter.ItemFilter
+	dereference bool
+	// dot adds the "."/".." entries to the first batch
+	dot bool
+	// limit is the max number of entries to print, 0 means unlimited
+	limit uint
+	// progress reports the number of entries printed to stderr
+	progress bool
+	// align pads the columns of each batch to the widest values printed so far
+	align bool
+	// header prints the header row before the first batch of each dir, see --header, it needs align
+	header bool
+}
+
+// setStreamPrinter makes p a printer able to print incrementally,
+// json is output as ndjson, and printers need the whole dir(grid, table...) are replaced by byline
+func setStreamPrinter() {
+	switch pp := p.(type) {
+	case *display.Byline, *display.Zero, *display.CSVPrinter, *display.TSVPrinter:
+	case *display.JsonPrinter:
+		pp.NDJSON = true
+	default:
+		p = display.NewByline()
+	}
+	display.Stream = true
+}
+
+// stream prints the entries of dir batch by batch,
+// returns the sub dirs for -R, the errors of entries and the error of opening the dir
+func (s *streamer) stream(dir string) (subDirs []*item.FileInfo, minorErrs []error, err error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	switch pp := p.(type) {
+	case *display.JsonPrinter:
+		pp.Extra = pp.Extra[:0]
+	case *display.CSVPrinter:
+		pp.ResetRawHeader()
+	case *display.TSVPrinter:
+		pp.ResetRawHeader()
+	}
+	p.DisablePostHook()
+
+	printed := uint(0)
+	var allPart []string
+	longestEachPart := make(map[string]int)
+	batch := make([]*item.FileInfo, 0, streamBatchSize+2)
+	if s.dot {
+		for _, name := range []string{".", ".."} {
+			info, err := dotEntry(dir, name)
+			if err != nil {
+				minorErrs = append(minorErrs, err)
+				continue
+			}
+			batch = append(batch, info)
+		}
+	}
+	for s.limit == 0 || printed < s.limit {
+		entries, readErr := f.ReadDir(streamBatchSize)
+		for _, v := range entries {
+			info, err := v.Info()
+			if err != nil {
+				minorErrs = append(minorErrs, err)
+				continue
+			}
+			fileInfo, err := item.NewFileInfoWithOption(
+				item.WithFileInfo(info), item.WithAbsPath(filepath.Join(dir, v.Name())),
+			)
+			if err != nil {
+				minorErrs = append(minorErrs, err)
+				continue
+			}
+			batch = append(batch, fileInfo)
+		}
+		batch = s.itemFilter.Filter(batch...)
+		if s.limit != 0 && printed+uint(len(batch)) > s.limit {
+			batch = batch[:s.limit-printed]
+		}
+		if s.dereference {
+			dereferenceInfos(batch)
+		}
+		for _, info := range batch {
+			if info.IsDir() && info.Name() != "." && info.Name() != ".." {
+				subDirs = append(subDirs, info)
+			}
+		}
+
+		if len(batch) != 0 {
+			contentFilter.GetDisplayItems(&batch)
+			if s.align {
+				// the columns are aligned to the widest values printed so far, which the header is made from
+				if allPart == nil {
+					allPart = batch[0].KeysByOrder()
+				}
+				display.AlignColumns(batch, allPart, longestEachPart)
+				if s.header && printed == 0 {
+					display.HeaderMaker{
+						Header:          true,
+						IsBefore:        true,
+						AllPart:         allPart,
+						LongestEachPart: longestEachPart,
+					}.Make(p, batch...)
+				}
+			}
+			p.Print(batch...)
+			printed += uint(len(batch))
+			if s.progress {
+				_, _ = fmt.Fprintf(os.Stderr, "\rstreamed %d entries", printed)
+			}
+		}
+		batch = batch[:0]
+
+		if errors.Is(readErr, io.EOF) {
+			break
+		} else if readErr != nil {
+			minorErrs = append(minorErrs, readErr)
+			break
+		}
+	}
+	if s.progress && printed != 0 {
+		_, _ = fmt.Fprintln(os.Stderr)
+	}
+	return subDirs, minorErrs, nil
+}
+
+// finishStream fires the post hooks after all batches are printed
+func finishStream() {
+	p.DisablePreHook()
+	p.EnablePostHook()
+	p.Print()
+	p.EnablePreHook()
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	contents "github.com/Equationzhao/g/internal/content"
+	"github.com/Equationzhao/g/internal/display"
+	"github.com/Equationzhao/g/internal/display/tree"
+	"github.com/Equationzhao/g/internal/item"
+	"github.com/Equationzhao/g/internal/util"
+)
+
+// summarizeTree caches the recursive file count, dir count and size of each dir in the tree, see --dir-summary.
+// the summaries are computed from the collected entries, so they respect the filters and --depth,
+// and the dirs not descended(at the depth limit or the skipped mountpoints) are not summarized
+func summarizeTree(infos []*item.FileInfo, depth int) {
+	display.NewTree(infos).Root.Summarize(func(node *tree.Node, s tree.Summary) {
+		info := node.Meta
+		if _, ok := info.Cache[contents.MountpointName]; ok || (depth >= 0 && node.Level >= depth) {
+			return
+		}
+		summary := plural(s.Files, "file") + ", " + plural(s.Dirs, "dir")
+		if s.Size > 0 {
+			size, _ := sizeEnabler.Size2String(s.Size)
+			summary += ", " + strings.TrimSpace(size)
+		}
+		info.Cache[contents.DirSummaryName] = []byte(summary)
+		info.Cache[contents.DirSizeName] = []byte(strconv.FormatInt(s.Size, 10))
+		info.Cache[contents.DirFilesName] = []byte(strconv.Itoa(s.Files))
+		raw, _ := json.Marshal(struct {
+			Files int   `json:"files"`
+			Dirs  int   `json:"dirs"`
+			Size  int64 `json:"size"`
+		}{s.Files, s.Dirs, s.Size})
+		info.SetJson("summary", raw)
+	})
+}
+
+// plural returns like '1 file' or '1,024 files'
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return util.Comma(int64(n)) + " " + unit + "s"
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	contents "github.com/Equationzhao/g/internal/content"
+	"github.com/Equationzhao/g/internal/display"
+	"github.com/Equationzhao/g/internal/item"
+	"github.com/Equationzhao/g/internal/util"
+)
+
+// moreInfo is the os.FileInfo of the synthetic entry replacing the entries over --tree-limit
+type moreInfo struct {
+	name string
+	size int64
+}
+
+func (m moreInfo) Name() string       { return m.name }
+func (m moreInfo) Size() int64        { return m.size }
+func (m moreInfo) Mode() os.FileMode  { return 0 }
+func (m moreInfo) ModTime() time.Time { return time.Time{} }
+func (m moreInfo) IsDir() bool        { return false }
+func (m moreInfo) Sys() any           { return nil }
+
+// moreGroup is the entries over --tree-limit in a dir
+type moreGroup struct {
+	count int
+	size  int64
+}
+
+// limitTree keeps at most limit children of each dir in the sorted tree entries before their columns are computed.
+// the rest and their descendants are returned as groups by their parents, see addMoreEntries,
+// the size of a group is the sum of the files in it.
+// the replaced entries are still counted in --statistic and the total size
+func limitTree(infos []*item.FileInfo, limit int) ([]*item.FileInfo, map[string]*moreGroup) {
+	children := make(map[string][]*item.FileInfo)
+	for _, info := range infos {
+		if parent, ok := info.Cache["parent"]; ok {
+			children[string(parent)] = append(children[string(parent)], info)
+		}
+	}
+	groups := make(map[string]*moreGroup)
+	hidden := make(map[string]*moreGroup)
+	for parent, c := range children {
+		if len(c) <= limit {
+			continue
+		}
+		g := &moreGroup{count: len(c) - limit}
+		groups[parent] = g
+		for _, info := range c[limit:] {
+			hidden[info.FullPath] = g
+		}
+	}
+	if len(groups) == 0 {
+		return infos, nil
+	}
+
+	// parents are visited before their children
+	byLevel := slices.Clone(infos)
+	slices.SortStableFunc(byLevel, func(a, b *item.FileInfo) int {
+		la, _ := strconv.Atoi(string(a.Cache["level"]))
+		lb, _ := strconv.Atoi(string(b.Cache["level"]))
+		return la - lb
+	})
+	for _, info := range byLevel {
+		g, ok := hidden[info.FullPath]
+		if !ok {
+			if g, ok = hidden[string(info.Cache["parent"])]; !ok {
+				continue
+			}
+			hidden[info.FullPath] = g
+		}
+		if !info.IsDir() {
+			g.size += info.Size()
+		}
+		nameToDisplay.Count(info)
+		sizeEnabler.AddTotal(info)
+	}
+
+	infos = slices.DeleteFunc(infos, func(info *item.FileInfo) bool {
+		_, ok := hidden[info.FullPath]
+		return ok
+	})
+	return infos, groups
+}
+
+// addMoreEntries adds a synthetic entry after the rendered entries of each dir in groups,
+// like '… 49,982 more entries (3.1 GiB)', see limitTree
+func addMoreEntries(infos []*item.FileInfo, groups map[string]*moreGroup, json bool) []*item.FileInfo {
+	for _, parent := range slices.Clone(infos) {
+		if g, ok := groups[parent.FullPath]; ok {
+			infos = append(infos, newMoreEntry(parent, g, json))
+		}
+	}
+	return infos
+}
+
+// newMoreEntry returns the synthetic entry of g in the dir parent,
+// its columns are empty except the name
+func newMoreEntry(parent *item.FileInfo, g *moreGroup, json bool) *item.FileInfo {
+	entries := "entries"
+	if g.count == 1 {
+		entries = "entry"
+	}
+	summary := util.Comma(int64(g.count)) + " more " + entries
+	if g.size > 0 {
+		s, _ := sizeEnabler.Size2String(g.size)
+		summary += " (" + s + ")"
+	}
+
+	info, _ := item.NewFileInfoWithOption(
+		item.WithFileInfo(moreInfo{name: summary, size: g.size}),
+		item.WithAbsPath(filepath.Join(parent.FullPath, summary)),
+	)
+	level, _ := strconv.Atoi(string(parent.Cache["level"]))
+	info.Cache["parent"] = []byte(parent.FullPath)
+	info.Cache["level"] = []byte(strconv.Itoa(level + 1))
+	for _, pair := range parent.Meta.Pairs() {
+		key, no := pair.Key(), pair.Value().NO()
+		switch key {
+		case "#":
+		case contents.NameName:
+			name := r.More(summary)
+			if json {
+				name = "… " + summary
+			}
+			info.Set(key, &display.ItemContent{Content: display.StringContent(name), No: no})
+		default:
+			info.Set(key, &display.ItemContent{Content: display.StringContent(""), No: no})
+		}
+	}
+	if json {
+		info.Set("more", &display.ItemContent{Content: display.StringContent(strconv.Itoa(g.count)), No: len(parent.Meta.Pairs())})
+		info.SetJson("more", []byte(strconv.Itoa(g.count)))
+	}
+	return info
+}
@@ -566,6 +566,24 @@ var viewFlag = []cli.Flag{
 		DisableDefaultText: true,
 		Category:           "VIEW",
 	},
+	&cli.BoolFlag{
+		Name:               "git-count",
+		Usage:              "show the number of changed entries in dirs by git status, like '3M 1?' [if git is installed]",
+		DisableDefaultText: true,
+		Category:           "VIEW",
+	},
+	&cli.BoolFlag{
+		Name:               "git-diffstat",
+		Usage:              "show the lines added and deleted in the worktree against HEAD, like '+12 −3', summed in dirs [if git is installed]",
+		DisableDefaultText: true,
+		Category:           "VIEW",
+	},
+	&cli.BoolFlag{
+		Name:               "git-diffstat-staged",
+		Usage:              "like --git-diffstat, but the index against HEAD [if git is installed]",
+		DisableDefaultText: true,
+		Category:           "VIEW",
+	},
 	&cli.BoolFlag{
 		Name:               "git-detail",
 		Usage:              "show git commit detail with hash, author, author date [if git is installed]",
@@ -581,7 +599,7 @@ var viewFlag = []cli.Flag{
 	},
 	&cli.BoolFlag{
 		Name:               "git-repo-status",
-		Usage:              "list root of git-tree status [if git is installed]",
+		Usage:              "list root of git-tree status, with commits ahead/behind upstream, stash count, detached HEAD and merge/rebase/cherry-pick/bisect in progress [if git is installed]",
 		Aliases:            []string{"repo-status"},
 		DisableDefaultText: true,
 		Category:           "VIEW",
@@ -805,12 +823,43 @@ var viewFlag = []cli.Flag{
 		DisableDefaultText: true,
 		Category:           "VIEW",
 	},
+	&cli.BoolFlag{
+		Name:               "from-stdin",
+		Usage:              "list the paths from stdin in tree, split by newline, eg: git ls-files | g --from-stdin",
+		DisableDefaultText: true,
+		Action: func(context *cli.Context, b bool) error {
+			if b {
+				_ = context.Set("tree", "1")
+			}
+			return nil
+		},
+		Category: "VIEW",
+	},
+	&cli.BoolFlag{
+		Name:               "stdin0",
+		Usage:              "like --from-stdin, split by NUL, eg: find . -print0 | g --stdin0",
+		DisableDefaultText: true,
+		Action: func(context *cli.Context, b bool) error {
+			if b {
+				_ = context.Set("tree", "1")
+			}
+			return nil
+		},
+		Category: "VIEW",
+	},
 	&cli.BoolFlag{
 		Name:               "flags",
 		Usage:              "list file flags[macOS only]",
 		DisableDefaultText: true,
 		Category:           "VIEW",
 	},
+	&cli.BoolFlag{
+		Name:               "extended",
+		Aliases:            []string{"@"},
+		Usage:              "list each file's extended attributes and sizes in long listing",
+		DisableDefaultText: true,
+		Category:           "VIEW",
+	},
 }
 
 func setLimit() {
@@ -107,6 +107,10 @@ func (cf *ContentFilter) GetDisplayItems(e *[]*item.FileInfo) {
 }
 
 func (cf *ContentFilter) processEntry(entry *item.FileInfo) error {
+	// the entries without metadata are rendered by the caller
+	if _, ok := entry.Cache[VirtualName]; ok {
+		return nil
+	}
 	for j, option := range cf.options {
 		stringContent, funcName := option(entry)
 		content := display.ItemContent{Content: display.StringContent(stringContent), No: j}
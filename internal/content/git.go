@@ -1,10 +1,13 @@
 package content
 
 import (
+	"encoding/json"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/Equationzhao/g/internal/align"
+	"github.com/Equationzhao/g/internal/display"
 	"github.com/Equationzhao/g/internal/git"
 	constval "github.com/Equationzhao/g/internal/global"
 	"github.com/Equationzhao/g/internal/item"
@@ -27,68 +30,149 @@ func NewGitEnabler() *GitEnabler {
 	}
 }
 
+// statusesOf returns the statuses of the repository of g.Path and the path of info relative to its top level
+func (g *GitEnabler) statusesOf(info *item.FileInfo) (git.FileGits, string, bool) {
+	gits, ok := g.cache.Get(g.Path)
+	if !ok {
+		return nil, "", false
+	}
+	topLevel, err := git.GetTopLevel(g.Path)
+	if err != nil {
+		return nil, "", false
+	}
+	rel, err := filepath.Rel(topLevel, info.FullPath)
+	if err != nil {
+		return nil, "", false
+	}
+	return *gits, rel, true
+}
+
+// Enable returns the git status like '-M', see git.StatusIn.
+// the changed submodules have their states like 'SC.U' before it, see git.SubmoduleStatus
 func (g *GitEnabler) Enable(renderer *render.Renderer) ContentOption {
-	isOrIsParentOf := func(parent, child string) bool {
-		if parent == child {
-			return true
+	return func(info *item.FileInfo) (string, string) {
+		x, y := git.Unmodified, git.Unmodified
+		var sub git.SubmoduleStatus
+		if gits, rel, ok := g.statusesOf(info); ok {
+			x, y = git.StatusIn(gits, rel)
+			sub = git.SubmoduleIn(gits, rel)
 		}
-		if strings.HasPrefix(child, parent+string(filepath.Separator)) {
-			return true
+		if display.Raw {
+			info.SetFields(
+				GitStatus,
+				item.Field{Name: "git_index", Value: x.String()},
+				item.Field{Name: "git_worktree", Value: y.String()},
+				item.Field{Name: "git_submodule", Value: sub.String()},
+			)
+			return x.String() + y.String(), GitStatus
 		}
-		return false
+		res := gitByName(x, renderer) + gitByName(y, renderer)
+		if sub != 0 {
+			// the column is right aligned, so the status stays in line with the other rows
+			res = renderer.GitSubmodule(sub.String()) + " " + res
+		}
+		return res, GitStatus
 	}
+}
 
+// EnableCount returns the number of the changed entries in dirs by status, like '3M 1?', see git.CountIn.
+// the column is empty for files and the dirs without changes
+func (g *GitEnabler) EnableCount(renderer *render.Renderer) ContentOption {
 	return func(info *item.FileInfo) (string, string) {
-		gits, ok := g.cache.Get(g.Path)
-		if ok {
-			topLevel, err := git.GetTopLevel(g.Path)
-			if err != nil {
-				return gitByName(git.Unmodified, renderer) + gitByName(git.Unmodified, renderer), GitStatus
+		var counts []git.StatusCount
+		if info.IsDir() {
+			if gits, rel, ok := g.statusesOf(info); ok {
+				counts = git.CountIn(gits, rel)
 			}
-			rel, err := filepath.Rel(topLevel, info.FullPath)
-			if err != nil {
-				return gitByName(git.Unmodified, renderer) + gitByName(git.Unmodified, renderer), GitStatus
+		}
+		if display.Raw {
+			m := make(map[string]int, len(counts))
+			for _, c := range counts {
+				m[c.Status.String()] = c.Count
 			}
-			for _, status := range *gits {
-				if status.X == git.Ignored || status.Y == git.Ignored {
-					// if status is ignored,
-					// and the file is or is a child of the ignored file
-					if isOrIsParentOf(status.Name, rel) {
-						return gitByName(status.X, renderer) + gitByName(status.Y, renderer), GitStatus
-					}
-				} else {
-					if isOrIsParentOf(rel, status.Name) {
-						return gitByName(status.X, renderer) + gitByName(status.Y, renderer), GitStatus
-					}
-				}
+			raw, _ := json.Marshal(m)
+			info.SetJson(GitCount, raw)
+		}
+		parts := make([]string, 0, len(counts))
+		for _, c := range counts {
+			s := strconv.Itoa(c.Count) + c.Status.String()
+			if !display.Raw {
+				s = gitRender(c.Status, renderer, s)
 			}
+			parts = append(parts, s)
 		}
-		return gitByName(git.Unmodified, renderer) + gitByName(git.Unmodified, renderer), GitStatus
+		return strings.Join(parts, " "), GitCount
+	}
+}
+
+// EnableDiffStat returns the lines added and deleted against HEAD, like '+12 −3', see git.DiffStatIn.
+// the worktree is compared if staged is not set, otherwise the index.
+// the binary files are marked instead, and the column is empty for the entries without changes
+func (g *GitEnabler) EnableDiffStat(renderer *render.Renderer, staged bool) ContentOption {
+	diff := git.GetDiffCache(staged)
+	return func(info *item.FileInfo) (string, string) {
+		var stat git.DiffStat
+		if topLevel, err := git.GetTopLevel(g.Path); err == nil {
+			if rel, err := filepath.Rel(topLevel, info.FullPath); err == nil {
+				stats, _ := diff.GetOrCompute(topLevel, git.DiffStatInit(topLevel, staged))
+				stat = git.DiffStatIn(stats, rel)
+			}
+		}
+		if display.Raw {
+			raw, _ := json.Marshal(struct {
+				Added   int `json:"added"`
+				Deleted int `json:"deleted"`
+				Binary  int `json:"binary"`
+			}{stat.Added, stat.Deleted, stat.Binary})
+			info.SetJson(GitDiffStat, raw)
+		}
+		var parts []string
+		if stat.Added > 0 || stat.Deleted > 0 {
+			added, deleted := strconv.Itoa(stat.Added), strconv.Itoa(stat.Deleted)
+			if display.Raw {
+				parts = append(parts, "+"+added, "−"+deleted)
+			} else {
+				parts = append(parts, renderer.GitDiffAdded(added), renderer.GitDiffDeleted(deleted))
+			}
+		}
+		if stat.Binary > 0 {
+			if display.Raw {
+				parts = append(parts, "bin")
+			} else {
+				parts = append(parts, renderer.GitDiffBinary())
+			}
+		}
+		return strings.Join(parts, " "), GitDiffStat
 	}
 }
 
 func gitByName(status git.Status, renderer *render.Renderer) string {
+	return gitRender(status, renderer, status.String())
+}
+
+// gitRender renders s in the color of the status
+func gitRender(status git.Status, renderer *render.Renderer, s string) string {
 	switch status {
 	case git.Unmodified:
-		return renderer.GitUnmodified("-")
+		return renderer.GitUnmodified(s)
 	case git.Modified:
-		return renderer.GitModified("M")
+		return renderer.GitModified(s)
 	case git.Added:
-		return renderer.GitAdded("A")
+		return renderer.GitAdded(s)
 	case git.Deleted:
-		return renderer.GitDeleted("D")
+		return renderer.GitDeleted(s)
 	case git.Renamed:
-		return renderer.GitRenamed("R")
+		return renderer.GitRenamed(s)
 	case git.Copied:
-		return renderer.GitCopied("C")
+		return renderer.GitCopied(s)
 	case git.Untracked:
-		return renderer.GitUntracked("?")
+		return renderer.GitUntracked(s)
 	case git.Ignored:
-		return renderer.GitIgnored("!")
+		return renderer.GitIgnored(s)
 	case git.TypeChanged:
-		return renderer.GitTypeChanged("T")
+		return renderer.GitTypeChanged(s)
 	case git.UpdatedButUnmerged:
-		return renderer.GitUpdatedButUnmerged("U")
+		return renderer.GitUpdatedButUnmerged(s)
 	default:
 		return ""
 	}
@@ -96,6 +180,8 @@ func gitByName(status git.Status, renderer *render.Renderer) string {
 
 const (
 	GitStatus     = constval.NameOfGitStatus
+	GitCount      = constval.NameOfGitCount
+	GitDiffStat   = constval.NameOfGitDiffStat
 	GitRepoBranch = constval.NameOfGitRepoBranch
 	GitRepoStatus = constval.NameOfGitRepoStatus
 	GitCommitHash = constval.NameOfGitCommitHash
@@ -117,10 +203,51 @@ func (g *GitRepoEnabler) EnableStatus(renderer *render.Renderer) ContentOption {
 	align.Register(GitRepoStatus)
 	return func(info *item.FileInfo) (string, string) {
 		// get repo status
-		return renderer.GitRepoStatus(git.GetRepoStatus(info.FullPath)), GitRepoStatus
+		repo := git.GetRepoInfo(info.FullPath)
+		if repo.Status == git.RepoStatusSkip {
+			return renderer.GitRepoStatus(repo.Status), GitRepoStatus
+		}
+		raw, _ := json.Marshal(struct {
+			Status    string `json:"status"`
+			Upstream  string `json:"upstream,omitempty"`
+			Ahead     int    `json:"ahead"`
+			Behind    int    `json:"behind"`
+			Stash     int    `json:"stash"`
+			Detached  string `json:"detached,omitempty"`
+			Operation string `json:"operation,omitempty"`
+		}{
+			repoStatusName(repo.Status), repo.Upstream, repo.Ahead, repo.Behind, repo.Stash, repo.Detached, repo.Operation,
+		})
+		info.SetJson(GitRepoStatus, raw)
+
+		parts := []string{renderer.GitRepoStatus(repo.Status)}
+		if repo.Ahead > 0 {
+			parts = append(parts, renderer.GitRepoAhead(strconv.Itoa(repo.Ahead)))
+		}
+		if repo.Behind > 0 {
+			parts = append(parts, renderer.GitRepoBehind(strconv.Itoa(repo.Behind)))
+		}
+		if repo.Stash > 0 {
+			parts = append(parts, renderer.GitRepoStash(strconv.Itoa(repo.Stash)))
+		}
+		if repo.Detached != "" {
+			parts = append(parts, renderer.GitRepoDetached(repo.Detached))
+		}
+		if repo.Operation != "" {
+			parts = append(parts, renderer.GitRepoOperation(strings.ToUpper(repo.Operation)))
+		}
+		return strings.Join(parts, " "), GitRepoStatus
 	}
 }
 
+// repoStatusName returns the name of the status in json
+func repoStatusName(status git.RepoStatus) string {
+	if status == git.RepoStatusDirty {
+		return "dirty"
+	}
+	return "clean"
+}
+
 func NewGitRepoEnabler() *GitRepoEnabler {
 	return &GitRepoEnabler{}
 }
@@ -163,6 +290,11 @@ func (g *GitCommitEnabler) EnableAuthorDateWithTimeFormat(renderer *render.Rende
 	}
 }
 
+// Close stops reading the history of the repositories once the entries are listed, see git.CloseCommitLogs
+func (g *GitCommitEnabler) Close() {
+	git.CloseCommitLogs()
+}
+
 func NewGitCommitEnabler() *GitCommitEnabler {
 	return &GitCommitEnabler{
 		Cache: haxmap.New[string, git.CommitInfo](10),
@@ -1,7 +1,10 @@
 package content
 
 import (
+	"strconv"
+
 	"github.com/Equationzhao/g/internal/align"
+	"github.com/Equationzhao/g/internal/display"
 	constval "github.com/Equationzhao/g/internal/global"
 	"github.com/Equationzhao/g/internal/item"
 	"github.com/Equationzhao/g/internal/osbased"
@@ -25,6 +28,12 @@ func (i *InodeEnabler) Enable(renderer *render.Renderer) ContentOption {
 		} else {
 			i = osbased.Inode(info)
 		}
+		if display.Raw {
+			if _, err := strconv.ParseUint(i, 10, 64); err == nil {
+				info.SetJson(Inode, []byte(i))
+			}
+			return i, Inode
+		}
 		return renderer.Inode(i), Inode
 	}
 }
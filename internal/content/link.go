@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"github.com/Equationzhao/g/internal/align"
+	"github.com/Equationzhao/g/internal/display"
 
 	constval "github.com/Equationzhao/g/internal/global"
 	"github.com/Equationzhao/g/internal/item"
@@ -23,6 +24,11 @@ const Link = constval.NameOfLink
 func (l *LinkEnabler) Enable(renderer *render.Renderer) ContentOption {
 	align.RegisterHeaderFooter(Link)
 	return func(info *item.FileInfo) (string, string) {
-		return renderer.Link(strconv.FormatUint(osbased.LinkCount(info), 10)), Link
+		n := strconv.FormatUint(osbased.LinkCount(info), 10)
+		if display.Raw {
+			info.SetJson(Link, []byte(n))
+			return n, Link
+		}
+		return renderer.Link(n), Link
 	}
 }
@@ -35,6 +35,27 @@ func MountsOn(path string) string {
 	return ""
 }
 
+// FsTypeOf returns the filesystem type of the mountpoint, empty if the path is not a mountpoint
+func FsTypeOf(mountpoint string) string {
+	err := mountsOnce.Do(func() error {
+		mount, err := disk.Partitions(true)
+		if err != nil {
+			return err
+		}
+		mounts = mount
+		return nil
+	})
+	if err != nil {
+		return ""
+	}
+	for _, stat := range mounts {
+		if stat.Mountpoint == mountpoint {
+			return stat.Fstype
+		}
+	}
+	return ""
+}
+
 var (
 	mounts     = make([]disk.PartitionStat, 10)
 	mountsOnce = util.Once{}
@@ -2,6 +2,14 @@
 
 package content
 
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/Equationzhao/g/internal/util"
+)
+
 // Lite version without mounts functionality
 // This reduces binary size by removing gopsutil dependency
 
@@ -9,3 +17,32 @@ func MountsOn(path string) string {
 	// In lite build, return empty string (no mount info)
 	return ""
 }
+
+// FsTypeOf returns the filesystem type of the mountpoint, empty if the path is not a mountpoint.
+// the lite version reads /proc/self/mounts, so it only works on Linux
+func FsTypeOf(mountpoint string) string {
+	_ = procMountsOnce.Do(func() error {
+		f, err := os.Open("/proc/self/mounts")
+		if err != nil {
+			return nil
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			// device mountpoint fstype options dump pass
+			fields := strings.Fields(scanner.Text())
+			if len(fields) >= 3 {
+				procMounts[procMountsUnescaper.Replace(fields[1])] = fields[2]
+			}
+		}
+		return nil
+	})
+	return procMounts[mountpoint]
+}
+
+var (
+	procMounts     = make(map[string]string)
+	procMountsOnce = util.Once{}
+	// spaces and backslashes in mountpoints are escaped in octal
+	procMountsUnescaper = strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`)
+)
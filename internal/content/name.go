@@ -65,6 +65,21 @@ func (s *Statistics) String() string {
 	return fmt.Sprintf("%d file(s), %d dir(s), %d link(s)", s.file.Load(), s.dir.Load(), s.link.Load())
 }
 
+// Count counts info in the statistics like the name column does, for the entries not rendered
+func (n *Name) Count(info *item.FileInfo) {
+	if n.statistics == nil {
+		return
+	}
+	switch {
+	case info.IsDir():
+		n.statistics.dir.Add(1)
+	case util.IsSymLinkMode(info.Mode()) || osbased.IsMacOSAlias(info.FullPath):
+		n.statistics.link.Add(1)
+	default:
+		n.statistics.file.Add(1)
+	}
+}
+
 func (n *Name) SetNoDeference() *Name {
 	n.noDeference = true
 	return n
@@ -188,8 +203,44 @@ func NewNameEnabler() *Name {
 	return &Name{}
 }
 
+// Clone returns a copy of n with the same options, its statistics(if set) start from zero
+func (n *Name) Clone() *Name {
+	c := *n
+	if n.statistics != nil {
+		c.statistics = &Statistics{}
+	}
+	return &c
+}
+
 const NameName = global.NameOfName
 
+// MountpointName is the key in item.FileInfo.Cache of the mount info of a mountpoint not descended,
+// see --one-file-system
+const MountpointName = "mountpoint"
+
+// NamePrefixName is the key in item.FileInfo.Cache of the prefix of the displayed name,
+// eg: the names of the dirs merged into the dir by --compact-dirs, or the relative dir in --flat
+const NamePrefixName = "name_prefix"
+
+// DirSummaryName is the key in item.FileInfo.Cache of the summary of a dir in the tree, like '12 files, 3 dirs, 1.2 MiB',
+// see --dir-summary. its recursive size and file count are cached in DirSizeName and DirFilesName for sorting
+const (
+	DirSummaryName = "summary"
+	DirSizeName    = "summary_size"
+	DirFilesName   = "summary_files"
+)
+
+// DirErrorName is the key in item.FileInfo.Cache of the error of reading the dir in the tree, like 'permission denied',
+// see --tree-errors
+const DirErrorName = "dir_error"
+
+// VirtualName is the key in item.FileInfo.Cache of the entries not existing on disk,
+// they are skipped by ContentFilter and only have names, see --from-stdin
+const VirtualName = "virtual"
+
+// LoopName is the key in item.FileInfo.Cache of the ancestor a followed symlink loops to, see --follow-links
+const LoopName = "loop"
+
 func makeLink(abs, name string) string {
 	return util.MakeLink(abs, name)
 }
@@ -378,6 +429,9 @@ func (n *Name) Enable(renderer *render.Renderer) ContentOption {
 		if n.mounts {
 			mounts = MountsOn(info.FullPath)
 		}
+		if m, ok := info.Cache[MountpointName]; ok && mounts == "" {
+			mounts = string(m)
+		}
 
 		if n.relativeTo != "" {
 			relativePath, err := filepath.Rel(n.relativeTo, info.FullPath)
@@ -386,6 +440,8 @@ func (n *Name) Enable(renderer *render.Renderer) ContentOption {
 			}
 		} else if n.fullPath {
 			name = info.FullPath
+		} else if prefix, ok := info.Cache[NamePrefixName]; ok {
+			name = string(prefix) + name
 		}
 
 		name = util.Escape(name)
@@ -455,6 +511,30 @@ func (n *Name) Enable(renderer *render.Renderer) ContentOption {
 				_, _ = b.WriteString(renderer.Mounts(mounts))
 			}
 		}
+		if summary, ok := info.Cache[DirSummaryName]; ok {
+			if n.json {
+				info.Meta.Set("summary", &display.ItemContent{Content: display.StringContent(summary)})
+			} else {
+				_ = b.WriteByte(' ')
+				_, _ = b.WriteString(renderer.DirSummary(string(summary)))
+			}
+		}
+		if reason, ok := info.Cache[DirErrorName]; ok {
+			if n.json {
+				info.Meta.Set("error", &display.ItemContent{Content: display.StringContent(reason)})
+			} else {
+				_ = b.WriteByte(' ')
+				_, _ = b.WriteString(renderer.DirError(string(reason)))
+			}
+		}
+		if loop, ok := info.Cache[LoopName]; ok {
+			if n.json {
+				info.Meta.Set("loop", &display.ItemContent{Content: display.StringContent(loop)})
+			} else {
+				_ = b.WriteByte(' ')
+				_, _ = b.WriteString(renderer.Loop(string(loop)))
+			}
+		}
 		return b.String(), NameName
 	}
 }
@@ -1,9 +1,12 @@
 package content
 
 import (
+	"fmt"
+	"os"
 	"strconv"
 
 	"github.com/Equationzhao/g/internal/align"
+	"github.com/Equationzhao/g/internal/display"
 	constval "github.com/Equationzhao/g/internal/global"
 	"github.com/Equationzhao/g/internal/item"
 	"github.com/Equationzhao/g/internal/render"
@@ -16,6 +19,15 @@ const Permissions = constval.NameOfPermission
 func EnableFileMode(renderer *render.Renderer) ContentOption {
 	align.Register(Permissions)
 	return func(info *item.FileInfo) (string, string) {
+		if display.Raw {
+			perm := renderer.FileMode(info.Mode().String())
+			info.SetFields(
+				Permissions,
+				item.Field{Name: "permissions", Value: perm},
+				item.Field{Name: "permissions_octal", Value: octalMode(info.Mode())},
+			)
+			return perm, Permissions
+		}
 		perm := renderer.FileMode(info.Mode().String())
 		list, _ := xattr.LList(info.FullPath)
 		if len(list) != 0 {
@@ -25,6 +37,21 @@ func EnableFileMode(renderer *render.Renderer) ContentOption {
 	}
 }
 
+// octalMode returns the permission bits with setuid, setgid and sticky bits in octal, like 0755 or 4755
+func octalMode(m os.FileMode) string {
+	o := uint32(m.Perm())
+	if m&os.ModeSetuid != 0 {
+		o |= 0o4000
+	}
+	if m&os.ModeSetgid != 0 {
+		o |= 0o2000
+	}
+	if m&os.ModeSticky != 0 {
+		o |= 0o1000
+	}
+	return fmt.Sprintf("%04o", o)
+}
+
 const OctalPermissions = "Octal"
 
 func EnableFileOctalPermissions(renderer *render.Renderer) ContentOption {
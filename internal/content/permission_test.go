@@ -0,0 +1,25 @@
+package content
+
+import (
+	"os"
+	"testing"
+)
+
+func Test_octalMode(t *testing.T) {
+	tests := []struct {
+		mode os.FileMode
+		want string
+	}{
+		{0o644, "0644"},
+		{os.ModeDir | 0o755, "0755"},
+		{os.ModeSetuid | 0o755, "4755"},
+		{os.ModeSetgid | 0o750, "2750"},
+		{os.ModeDir | os.ModeSticky | 0o777, "1777"},
+		{os.ModeSymlink | 0o777, "0777"},
+	}
+	for _, tt := range tests {
+		if got := octalMode(tt.mode); got != tt.want {
+			t.Errorf("octalMode(%v) = %v, want %v", tt.mode, got, tt.want)
+		}
+	}
+}
@@ -3,9 +3,11 @@ package content
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"sync/atomic"
 
 	"github.com/Equationzhao/g/internal/align"
+	"github.com/Equationzhao/g/internal/display"
 
 	constval "github.com/Equationzhao/g/internal/global"
 	"github.com/Equationzhao/g/internal/item"
@@ -56,6 +58,31 @@ func ParseSize(size string) (Size, error) {
 	return Size{Bytes: uint64(sizeFloat * float64(CountBytes(sizeUnit)))}, nil
 }
 
+// ParseSizeWithSI parses the size like ParseSize,
+// but K/M/G/T(KB/MB/GB/TB) are powers of 1024 unless isSI is set, KiB/MiB/GiB/TiB are always powers of 1024.
+// a size without unit is in bytes
+func ParseSizeWithSI(size string, isSI bool) (Size, error) {
+	sizeFloat, unit := util.SplitNumberAndUnit(strings.TrimSpace(size))
+	if sizeFloat < 0 {
+		return Size{}, fmt.Errorf("size can't be negative")
+	}
+	var sizeUnit SizeUnit
+	switch unit {
+	case "":
+		sizeUnit = Byte
+	case "KiB", "MiB", "GiB", "TiB":
+		sizeUnit = string2SizeUnit(unit)
+	case "K":
+		sizeUnit = ConvertFromSizeString("k", isSI)
+	default:
+		sizeUnit = ConvertFromSizeString(unit, isSI)
+	}
+	if sizeUnit <= Bit {
+		return Size{}, fmt.Errorf("invalid size unit: %s", unit)
+	}
+	return Size{Bytes: uint64(sizeFloat * float64(CountBytes(sizeUnit)))}, nil
+}
+
 type SizeUnit float64
 
 func sizeStringSets(size SizeUnit) []string {
@@ -161,9 +188,11 @@ func ConvertFromSizeString(size string, isSI bool) SizeUnit {
 type SizeEnabler struct {
 	total       atomic.Int64
 	enableTotal bool
-	sizeUint    SizeUnit
-	recursive   *SizeRecursive
-	isSi        bool
+	// column is set by EnableSize, the total is only summed by the size column
+	column    bool
+	sizeUint  SizeUnit
+	recursive *SizeRecursive
+	isSi      bool
 }
 
 func (s *SizeEnabler) Recursive() *SizeRecursive {
@@ -196,6 +225,17 @@ func NewSizeEnabler() *SizeEnabler {
 	}
 }
 
+// Clone returns a copy of s with the same options, its total starts from zero
+func (s *SizeEnabler) Clone() *SizeEnabler {
+	return &SizeEnabler{
+		enableTotal: s.enableTotal,
+		column:      s.column,
+		sizeUint:    s.sizeUint,
+		recursive:   s.recursive,
+		isSi:        s.isSi,
+	}
+}
+
 func (s *SizeEnabler) SizeUint() SizeUnit {
 	return s.sizeUint
 }
@@ -221,6 +261,25 @@ func (s *SizeEnabler) Reset() {
 	}
 }
 
+// AddTotal adds the size of info to the total like the size column does, for the entries not rendered
+func (s *SizeEnabler) AddTotal(info *item.FileInfo) {
+	if s.enableTotal && s.column {
+		s.total.Add(s.sizeOf(info))
+	}
+}
+
+func (s *SizeEnabler) sizeOf(info *item.FileInfo) int64 {
+	if s.recursive == nil {
+		return info.Size()
+	}
+	if r, ok := info.Cache[RecursiveSizeName]; ok {
+		// convert []byte to int64
+		v, _ := strconv.ParseInt(string(r), 10, 64)
+		return v
+	}
+	return util.RecursivelySizeOf(info, s.recursive.depth)
+}
+
 func (s *SizeEnabler) Size2String(b int64) (string, SizeUnit) {
 	var res string
 	actualUnit := s.sizeUint
@@ -273,22 +332,18 @@ const RecursiveSizeName = "recursive_size"
 
 func (s *SizeEnabler) EnableSize(size SizeUnit, renderer *render.Renderer) ContentOption {
 	s.sizeUint = size
+	s.column = true
 	align.RegisterHeaderFooter(SizeName)
 	return func(info *item.FileInfo) (string, string) {
-		var v int64
-		if s.recursive != nil {
-			if r, ok := info.Cache[RecursiveSizeName]; ok {
-				// convert []byte to int64
-				v, _ = strconv.ParseInt(string(r), 10, 64)
-			} else {
-				v = util.RecursivelySizeOf(info, s.recursive.depth)
-			}
-		} else {
-			v = info.Size()
-		}
+		v := s.sizeOf(info)
 		if s.enableTotal {
 			s.total.Add(v)
 		}
+		if display.Raw {
+			res := strconv.FormatInt(v, 10)
+			info.SetJson(SizeName, []byte(res))
+			return res, SizeName
+		}
 		res, unit := s.Size2String(v)
 		return renderer.Size(res, Convert2SizeString(unit)), SizeName
 	}
@@ -307,6 +362,11 @@ func (b *BlockSizeEnabler) Enable(renderer *render.Renderer) ContentOption {
 	return func(info *item.FileInfo) (string, string) {
 		res := ""
 		bs := osbased.BlockSize(info)
+		if display.Raw {
+			res = strconv.FormatInt(bs, 10)
+			info.SetJson(BlockSizeName, []byte(res))
+			return res, BlockSizeName
+		}
 		if bs == 0 {
 			res = "-"
 		} else {
@@ -45,3 +45,34 @@ func TestParseSize(t *testing.T) {
 	testHelper("3,123,432.321tb", 3_123_432.321, TB)
 	testHelper("3,123,432.321t", 3_123_432.321, TB)
 }
+
+func TestParseSizeWithSI(t *testing.T) {
+	tests := []struct {
+		size string
+		isSI bool
+		want uint64
+	}{
+		{"100", false, 100},
+		{"10K", false, 10 * 1024},
+		{"10K", true, 10 * 1000},
+		{"1.5M", false, 1.5 * 1024 * 1024},
+		{"1GB", true, 1000 * 1000 * 1000},
+		{"1GiB", true, 1024 * 1024 * 1024},
+		{"2t", false, 2 << 40},
+	}
+	for _, tt := range tests {
+		got, err := ParseSizeWithSI(tt.size, tt.isSI)
+		if err != nil {
+			t.Errorf("ParseSizeWithSI(%s, %v) error = %v", tt.size, tt.isSI, err)
+			continue
+		}
+		if got.Bytes != tt.want {
+			t.Errorf("ParseSizeWithSI(%s, %v) = %d, want %d", tt.size, tt.isSI, got.Bytes, tt.want)
+		}
+	}
+	for _, s := range []string{"-1K", "10X", "1bit"} {
+		if _, err := ParseSizeWithSI(s, false); err == nil {
+			t.Errorf("ParseSizeWithSI(%s) should return an error", s)
+		}
+	}
+}
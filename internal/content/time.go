@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Equationzhao/g/internal/display"
 	constval "github.com/Equationzhao/g/internal/global"
 	"github.com/Equationzhao/g/internal/item"
 	"github.com/Equationzhao/g/internal/osbased"
@@ -91,6 +92,9 @@ func EnableTime(format, mode string, renderer *render.Renderer) ContentOption {
 		}
 
 		var timeString string
+		if display.Raw {
+			return t.Format(time.RFC3339Nano), timeName + " " + timeType
+		}
 		if strings.HasPrefix(format, "+") {
 			timeString = strftime.Format(t, strings.TrimPrefix(format, "+"))
 		} else {
@@ -0,0 +1,67 @@
+package content
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/Equationzhao/g/internal/align"
+	constval "github.com/Equationzhao/g/internal/global"
+	"github.com/Equationzhao/g/internal/item"
+	"github.com/Equationzhao/g/internal/render"
+	"github.com/pkg/xattr"
+)
+
+type XattrEnabler struct{}
+
+func NewXattrEnabler() *XattrEnabler {
+	return &XattrEnabler{}
+}
+
+const Xattrs = constval.NameOfXattrs
+
+// Xattr is an extended attribute of a file
+type Xattr struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+}
+
+// ListXattrs returns the extended attributes of the file without following symlinks,
+// the size is the length of the value in bytes
+func ListXattrs(path string) []Xattr {
+	names, err := xattr.LList(path)
+	if err != nil || len(names) == 0 {
+		return nil
+	}
+	res := make([]Xattr, 0, len(names))
+	for _, name := range names {
+		value, err := xattr.LGet(path, name)
+		if err != nil {
+			continue
+		}
+		res = append(res, Xattr{Name: name, Size: len(value)})
+	}
+	return res
+}
+
+// Enable returns the extended attributes with the size of value, like user.tag(12),user.checksum(64)
+// in json, a list of {"name", "size"} is output
+func (x *XattrEnabler) Enable(renderer *render.Renderer) ContentOption {
+	align.Register(Xattrs)
+	return func(info *item.FileInfo) (string, string) {
+		attrs := ListXattrs(info.FullPath)
+		raw, _ := json.Marshal(attrs)
+		if attrs == nil {
+			raw = []byte("[]")
+		}
+		info.SetJson(Xattrs, raw)
+		if len(attrs) == 0 {
+			return renderer.Xattr("-"), Xattrs
+		}
+		s := make([]string, 0, len(attrs))
+		for _, a := range attrs {
+			s = append(s, a.Name+"("+strconv.Itoa(a.Size)+")")
+		}
+		return renderer.Xattr(strings.Join(s, ",")), Xattrs
+	}
+}
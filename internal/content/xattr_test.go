@@ -0,0 +1,26 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/xattr"
+)
+
+func TestListXattrs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := ListXattrs(path); got != nil {
+		t.Errorf("ListXattrs() = %v, want nil", got)
+	}
+	if err := xattr.Set(path, "user.tag", []byte("hello")); err != nil {
+		t.Skipf("xattr is not supported: %v", err)
+	}
+	got := ListXattrs(path)
+	if len(got) != 1 || got[0].Name != "user.tag" || got[0].Size != 5 {
+		t.Errorf("ListXattrs() = %v, want [{user.tag 5}]", got)
+	}
+}
@@ -85,3 +85,38 @@ func (h HeaderMaker) Make(p Printer, Items ...*item.FileInfo) {
 		}
 	}
 }
+
+// AlignColumns pads every part except the name to the width of its longest value,
+// the longest width of each part is recorded in longestEachPart, which is shared with HeaderMaker
+func AlignColumns(infos []*item.FileInfo, allPart []string, longestEachPart map[string]int) {
+	for _, it := range infos {
+		for _, part := range allPart {
+			content, ok := it.Get(part)
+			if ok && part != constval.NameOfName {
+				l := WidthNoHyperLinkLen(content.String())
+				if l > longestEachPart[part] {
+					longestEachPart[part] = l
+				}
+			}
+		}
+	}
+
+	// expand the length of each part using the scan result
+	for _, it := range infos {
+		for _, part := range allPart {
+			if part != constval.NameOfName {
+				content, _ := it.Get(part)
+				l := WidthNoHyperLinkLen(content.String())
+				if l < longestEachPart[part] {
+					expand := content.SetPrefix
+					if align.IsLeft(part) {
+						expand = content.SetSuffix
+					}
+					// expand
+					expand(strings.Repeat(" ", longestEachPart[part]-l))
+					it.Set(part, content)
+				}
+			}
+		}
+	}
+}
@@ -469,6 +469,17 @@ type JsonPrinter struct {
 	*bufio.Writer
 	*hook
 	Extra []any // RawPrint can't output in json, so we need an extra field
+	// Multiple wraps the output of each Print into a single json array of {path, entries, extra},
+	// set when more than one dir is listed(multiple paths or -R), Close must be called to end the array
+	Multiple bool
+	// NDJSON outputs one entry per line, with the listed dir as "dir"
+	NDJSON bool
+	// Tree outputs the entries of --tree as nested nodes with their children
+	Tree bool
+	// YAML outputs yaml instead of json, multiple dirs are output as multiple yaml documents
+	YAML    bool
+	path    string
+	printed int
 }
 
 func NewJsonPrinter() Printer {
@@ -479,6 +490,34 @@ func NewJsonPrinter() Printer {
 	}
 }
 
+// NewNDJsonPrinter returns a JsonPrinter which outputs newline delimited json
+func NewNDJsonPrinter() Printer {
+	return &JsonPrinter{
+		Writer: bufio.NewWriter(Output),
+		hook:   newHook(),
+		Extra:  make([]any, 0),
+		NDJSON: true,
+	}
+}
+
+// SetPath sets the listed dir of the next Print
+func (j *JsonPrinter) SetPath(path string) {
+	j.path = path
+}
+
+// Close ends the json array when Multiple is set
+func (j *JsonPrinter) Close() {
+	if !j.Multiple || j.NDJSON || j.YAML {
+		return
+	}
+	defer j.Flush()
+	if j.printed == 0 {
+		_, _ = j.WriteString("[]\n")
+		return
+	}
+	_, _ = j.WriteString("\n]\n")
+}
+
 var makeJsonFieldNameReplacer = strings.NewReplacer(" ", "_", "-", "_")
 
 func makeJsonFieldName(s string) string {
@@ -491,22 +530,146 @@ func (j *JsonPrinter) Print(items ...*item.FileInfo) {
 	}
 	defer j.Flush()
 
-	list := make([]*orderedmap.OrderedMap[string, string], 0, len(items))
+	key, list := "entries", j.entries(items)
+	if j.Tree && len(items) != 0 {
+		key, list = "tree", []*orderedmap.OrderedMap[string, any]{j.treeEntry(NewTree(items).Root)}
+	}
+	doc := orderedmap.New[string, any]()
+	if j.Multiple {
+		doc.Set("path", j.path)
+	}
+	if !j.Multiple && len(j.Extra) != 0 {
+		doc.Set("extra", j.Extra)
+	}
+	if j.Tree && len(list) != 0 {
+		doc.Set(key, list[0])
+	} else if j.Multiple || len(list) != 0 {
+		doc.Set(key, list)
+	}
+	if j.Multiple && len(j.Extra) != 0 {
+		doc.Set("extra", j.Extra)
+	}
+
+	switch {
+	case j.NDJSON:
+		j.printNDJson(list)
+	case j.YAML:
+		if j.Multiple && j.printed != 0 {
+			_, _ = j.WriteString("---\n")
+		}
+		b, err := marshalYaml(doc)
+		if err != nil {
+			_, _ = j.WriteString(err.Error() + "\n")
+			return
+		}
+		_, _ = j.Write(b)
+	case j.Multiple:
+		if j.printed == 0 {
+			_, _ = j.WriteString("[\n\t")
+		} else {
+			_, _ = j.WriteString(",\n\t")
+		}
+		pretty, err := json.MarshalIndent(doc, "\t", "	")
+		if err != nil {
+			_, _ = j.WriteString(err.Error() + "\n")
+			return
+		}
+		_, _ = j.Write(pretty)
+	default:
+		pretty, err := json.MarshalIndent(doc, "", "	")
+		if err != nil {
+			_, _ = j.WriteString(err.Error() + "\n")
+			return
+		}
+		_, _ = j.Write(pretty)
+		_, _ = j.WriteString("\n")
+	}
+	j.printed++
+	if !j.disableAfter {
+		fire(j.AfterPrint, j, items...)
+	}
+}
+
+// printNDJson prints each entry in one line with the listed dir,
+// the extra is printed in the last line as {"dir": ..., "extra": [...]} if any
+func (j *JsonPrinter) printNDJson(list []*orderedmap.OrderedMap[string, any]) {
+	for _, entry := range list {
+		line := orderedmap.New[string, any](
+			orderedmap.WithCapacity[string, any](entry.Len() + 1),
+		)
+		line.Set("dir", j.path)
+		for pair := entry.Oldest(); pair != nil; pair = pair.Next() {
+			line.Set(pair.Key, pair.Value)
+		}
+		j.writeJsonLine(line)
+	}
+	if len(j.Extra) != 0 {
+		j.writeJsonLine(&struct {
+			Dir   string `json:"dir"`
+			Extra []any  `json:"extra"`
+		}{
+			Dir:   j.path,
+			Extra: j.Extra,
+		})
+	}
+}
+
+func (j *JsonPrinter) writeJsonLine(v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		_, _ = j.WriteString(err.Error() + "\n")
+		return
+	}
+	_, _ = j.Write(b)
+	_, _ = j.WriteString("\n")
+}
+
+// treeEntry returns the entry of the node with its children,
+// dirs always have children, which is empty when the dir is empty or the depth limit is reached
+func (j *JsonPrinter) treeEntry(node *tree.Node) *orderedmap.OrderedMap[string, any] {
+	entry := j.entries([]*item.FileInfo{node.Meta})[0]
+	if !node.Meta.IsDir() && len(node.Child) == 0 {
+		return entry
+	}
+	children := make([]*orderedmap.OrderedMap[string, any], 0, len(node.Child))
+	for _, child := range node.Child {
+		children = append(children, j.treeEntry(child))
+	}
+	entry.Set("children", children)
+	return entry
+}
+
+// entries returns the entries as ordered maps, sorted by Content.No
+func (j *JsonPrinter) entries(items []*item.FileInfo) []*orderedmap.OrderedMap[string, any] {
+	list := make([]*orderedmap.OrderedMap[string, any], 0, len(items))
 	for _, v := range items {
+		if Raw {
+			s := orderedmap.New[string, any]()
+			for _, f := range rawFields(v) {
+				s.Set(f.Name, f.Value)
+			}
+			list = append(list, s)
+			continue
+		}
 		all := v.Meta.Pairs()
 
 		type orderItem struct {
 			name    string
-			content string
+			content any
 			no      int
 		}
 
 		order := make([]orderItem, 0, len(all))
 
 		// sort by v.Content.No
-		for _, v := range all {
-			if name := v.Key(); name != "#" {
-				order = append(order, orderItem{name: makeJsonFieldName(name), content: v.Value().String(), no: v.Value().NO()})
+		for _, pair := range all {
+			if name := pair.Key(); name != "#" {
+				var content any = pair.Value().String()
+				// structured content is output as it is
+				if raw, ok := v.Json(name); ok {
+					content = json.RawMessage(raw)
+				}
+				order = append(order, orderItem{name: makeJsonFieldName(name), content: content, no: pair.Value().NO()})
 			}
 		}
 
@@ -516,8 +679,8 @@ func (j *JsonPrinter) Print(items ...*item.FileInfo) {
 			},
 		)
 
-		s := orderedmap.New[string, string](
-			orderedmap.WithCapacity[string, string](len(order)),
+		s := orderedmap.New[string, any](
+			orderedmap.WithCapacity[string, any](len(order)),
 		)
 
 		list = append(list, s)
@@ -526,25 +689,7 @@ func (j *JsonPrinter) Print(items ...*item.FileInfo) {
 			s.Set(v.name, v.content)
 		}
 	}
-
-	wrap := &struct {
-		Extra   []any                                    `json:"extra,omitempty"`
-		Content []*orderedmap.OrderedMap[string, string] `json:"entries,omitempty"`
-	}{
-		Extra:   j.Extra,
-		Content: list,
-	}
-
-	pretty, err := json.MarshalIndent(wrap, "", "	")
-	if err != nil {
-		_, _ = j.WriteString(err.Error() + "\n")
-		return
-	}
-	_, _ = j.Write(pretty)
-	_, _ = j.WriteString("\n")
-	if !j.disableAfter {
-		fire(j.AfterPrint, j, items...)
-	}
+	return list
 }
 
 type PrettyPrinter interface {
@@ -559,6 +704,7 @@ type TablePrinter struct {
 	*hook
 	header, footer table.Row
 	w              table.Writer
+	rawHeader      []string
 }
 
 func (t *TablePrinter) SetTitle(title string) {
@@ -675,6 +821,10 @@ func NewCSVPrinter() Printer {
 }
 
 func (c *CSVPrinter) Print(s ...*item.FileInfo) {
+	if Raw {
+		c.printRaw(',', s...)
+		return
+	}
 	c.PrintBase(c.w.RenderCSV, s...)
 }
 
@@ -689,9 +839,50 @@ func NewTSVPrinter() Printer {
 }
 
 func (t *TSVPrinter) Print(s ...*item.FileInfo) {
+	if Raw {
+		t.printRaw('\t', s...)
+		return
+	}
 	t.PrintBase(t.w.RenderTSV, s...)
 }
 
+// NewTree builds the tree from the "level" and "parent" in item.FileInfo.Cache,
+// the item at level 0 is the root
+func NewTree(s []*item.FileInfo) *tree.Tree {
+	// split by full path
+	// the item sharing the same dir will be grouped together
+	// and the order is the same as the input
+	buildTree := tree.NewTree(tree.WithCap(len(s) / 2))
+	level := make(map[string][]*item.FileInfo)
+	for _, v := range s {
+		level[string(v.Cache["level"])] = append(level[string(v.Cache["level"])], v)
+	}
+
+	// root
+	l := len(level)
+	nodeMap := make(map[string]*tree.Node, l)
+
+	root := level["0"][0]
+	buildTree.Root.Meta = root
+	nodeMap[root.FullPath] = buildTree.Root
+
+	for i := 1; i < l; i++ {
+		for _, v := range level[strconv.Itoa(i)] {
+			node := nodeMap[string(v.Cache["parent"])]
+			c := &tree.Node{
+				Parent:     node,
+				Child:      make([]*tree.Node, 0, 10),
+				Level:      i,
+				Meta:       v,
+				Connectors: make([]string, i),
+			}
+			nodeMap[v.FullPath] = c
+			node.AddChild(c)
+		}
+	}
+	return buildTree
+}
+
 type TreePrinter struct {
 	*bufio.Writer
 	*hook
@@ -711,16 +902,8 @@ func (t *TreePrinter) Print(s ...*item.FileInfo) {
 	}
 	defer t.Flush()
 
-	// split by full path
-	// the item sharing the same dir will be grouped together
-	// and the order is the same as the input
 	total := len(s)
-
-	buildTree := tree.NewTree(tree.WithCap(total / 2))
-	level := make(map[string][]*item.FileInfo)
-	for _, v := range s {
-		level[string(v.Cache["level"])] = append(level[string(v.Cache["level"])], v)
-	}
+	buildTree := NewTree(s)
 
 	prefixAndName := func(info *item.FileInfo) (prefix, name string) {
 		v := info.ValuesByOrdered()
@@ -737,29 +920,6 @@ func (t *TreePrinter) Print(s ...*item.FileInfo) {
 		return prefix, name
 	}
 
-	// root
-	l := len(level)
-	nodeMap := make(map[string]*tree.Node, l)
-
-	root := level["0"][0]
-	buildTree.Root.Meta = root
-	nodeMap[root.FullPath] = buildTree.Root
-
-	for i := 1; i < l; i++ {
-		for _, v := range level[strconv.Itoa(i)] {
-			node := nodeMap[string(v.Cache["parent"])]
-			c := &tree.Node{
-				Parent:     node,
-				Child:      make([]*tree.Node, 0, 10),
-				Level:      i,
-				Meta:       v,
-				Connectors: make([]string, i),
-			}
-			nodeMap[v.FullPath] = c
-			node.AddChild(c)
-		}
-	}
-
 	Child := "├── "
 	LastChild := "╰── "
 	Mid := "│   "
@@ -0,0 +1,151 @@
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Equationzhao/g/internal/item"
+)
+
+func newJsonTestInfo(t *testing.T, name string) *item.FileInfo {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := item.NewFileInfo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info.Set("name", &ItemContent{No: 10, Content: StringContent(name)})
+	return info
+}
+
+func TestJsonPrinter_Multiple(t *testing.T) {
+	buf := &bytes.Buffer{}
+	Output = buf
+	defer func() { Output = os.Stdout }()
+
+	p := NewJsonPrinter().(*JsonPrinter)
+	p.Multiple = true
+	p.SetPath("a")
+	p.Print(newJsonTestInfo(t, "x"), newJsonTestInfo(t, "y"))
+	p.SetPath("a/b")
+	p.Extra = []any{map[string]int{"total": 1}}
+	p.Print(newJsonTestInfo(t, "z"))
+	p.Close()
+
+	var got []struct {
+		Path    string              `json:"path"`
+		Entries []map[string]string `json:"entries"`
+		Extra   []map[string]int    `json:"extra"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not a valid json document: %v\n%s", err, buf.String())
+	}
+	if len(got) != 2 || got[0].Path != "a" || got[1].Path != "a/b" {
+		t.Fatalf("got %+v", got)
+	}
+	if len(got[0].Entries) != 2 || got[0].Entries[1]["name"] != "y" || got[1].Extra[0]["total"] != 1 {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestJsonPrinter_MultipleEmpty(t *testing.T) {
+	buf := &bytes.Buffer{}
+	Output = buf
+	defer func() { Output = os.Stdout }()
+
+	p := NewJsonPrinter().(*JsonPrinter)
+	p.Multiple = true
+	p.Close()
+	if got := buf.String(); got != "[]\n" {
+		t.Errorf("got %q, want %q", got, "[]\n")
+	}
+}
+
+func TestJsonPrinter_NDJson(t *testing.T) {
+	buf := &bytes.Buffer{}
+	Output = buf
+	defer func() { Output = os.Stdout }()
+
+	p := NewNDJsonPrinter().(*JsonPrinter)
+	p.SetPath("a")
+	p.Print(newJsonTestInfo(t, "x"), newJsonTestInfo(t, "y"))
+	p.Close()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	for i, name := range []string{"x", "y"} {
+		var entry map[string]string
+		if err := json.Unmarshal([]byte(lines[i]), &entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry["dir"] != "a" || entry["name"] != name {
+			t.Errorf("line %d = %v", i, entry)
+		}
+	}
+}
+
+func TestJsonPrinter_Tree(t *testing.T) {
+	buf := &bytes.Buffer{}
+	Output = buf
+	defer func() { Output = os.Stdout }()
+
+	root := newJsonTestInfo(t, "root")
+	root.Cache["level"] = []byte("0")
+	a := newJsonTestInfo(t, "a")
+	a.Cache["level"], a.Cache["parent"] = []byte("1"), []byte(root.FullPath)
+	b := newJsonTestInfo(t, "b")
+	b.Cache["level"], b.Cache["parent"] = []byte("2"), []byte(a.FullPath)
+	c := newJsonTestInfo(t, "c")
+	c.Cache["level"], c.Cache["parent"] = []byte("1"), []byte(root.FullPath)
+
+	p := NewJsonPrinter().(*JsonPrinter)
+	p.Tree = true
+	p.Print(root, a, b, c)
+
+	type node struct {
+		Name     string `json:"name"`
+		Children []node `json:"children"`
+	}
+	var got struct {
+		Tree node `json:"tree"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not a valid json document: %v\n%s", err, buf.String())
+	}
+	want := node{Name: "root", Children: []node{{Name: "a", Children: []node{{Name: "b"}}}, {Name: "c"}}}
+	if !reflect.DeepEqual(got.Tree, want) {
+		t.Errorf("got %+v, want %+v", got.Tree, want)
+	}
+}
+
+func TestJsonPrinter_YAML(t *testing.T) {
+	buf := &bytes.Buffer{}
+	Output = buf
+	defer func() { Output = os.Stdout }()
+
+	p := NewYamlPrinter().(*JsonPrinter)
+	p.Multiple = true
+	x := newJsonTestInfo(t, "0755")
+	x.SetJson("size", []byte("12"))
+	x.Set("size", &ItemContent{No: 1, Content: StringContent("12 B")})
+	p.SetPath("a")
+	p.Print(x)
+	p.SetPath("b")
+	p.Print(newJsonTestInfo(t, "y"))
+	p.Close()
+
+	want := "path: a\nentries:\n    - size: 12\n      name: \"0755\"\n---\npath: b\nentries:\n    - name: y\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
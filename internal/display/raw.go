@@ -0,0 +1,115 @@
+package display
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/Equationzhao/g/internal/cached"
+	constval "github.com/Equationzhao/g/internal/global"
+	"github.com/Equationzhao/g/internal/item"
+)
+
+// Raw enables the machine-readable output of JsonPrinter, CSVPrinter and TSVPrinter:
+// every column is output with a snake_case name and a typed value, see docs/Raw.md
+var Raw = false
+
+// Stream is set when a dir is printed in batches, see --stream.
+// csv/tsv print the header once with the columns of the first batch
+var Stream = false
+
+// rawFields returns the columns of the entry ordered by No,
+// the columns split by item.FileInfo.SetFields are expanded
+func rawFields(info *item.FileInfo) []item.RawField {
+	pairs := info.Meta.Pairs()
+	slices.SortFunc(pairs, func(a, b cached.Pair[string, item.Item]) int {
+		return a.Value().NO() - b.Value().NO()
+	})
+	res := make([]item.RawField, 0, len(pairs))
+	for _, pair := range pairs {
+		name := pair.Key()
+		if name == constval.NameOfIndex {
+			continue
+		}
+		if fields, ok := info.Fields(name); ok {
+			res = append(res, fields...)
+			continue
+		}
+		value, ok := info.Json(name)
+		if !ok {
+			value, _ = json.Marshal(strings.TrimSpace(pair.Value().String()))
+		}
+		res = append(res, item.RawField{Name: makeJsonFieldName(name), Value: value})
+	}
+	return res
+}
+
+// rawString returns the value in csv/tsv, strings are unquoted and others are kept as json
+func rawString(v json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(v, &s); err == nil {
+		return s
+	}
+	return string(v)
+}
+
+// ResetRawHeader makes the next batch print the header row in Stream mode
+func (t *TablePrinter) ResetRawHeader() {
+	t.rawHeader = nil
+}
+
+// printRaw prints the entries as csv with a header row, comma is the field delimiter.
+// the header is the union of all columns, as some only exist for a few entries(eg: dereference of links)
+func (t *TablePrinter) printRaw(comma rune, s ...*item.FileInfo) {
+	if !t.disableBefore {
+		fire(t.BeforePrint, t, s...)
+	}
+	defer t.Flush()
+
+	rows := make([][]item.RawField, 0, len(s))
+	header := make([]string, 0)
+	// the header is already printed in the former batch
+	printed := Stream && t.rawHeader != nil
+	if printed {
+		header = t.rawHeader
+	}
+	for _, info := range s {
+		fields := rawFields(info)
+		for i, f := range fields {
+			if printed || slices.Contains(header, f.Name) {
+				continue
+			}
+			// keep the relative position of the column
+			at := 0
+			if i > 0 {
+				at = slices.Index(header, fields[i-1].Name) + 1
+			}
+			header = slices.Insert(header, at, f.Name)
+		}
+		rows = append(rows, fields)
+	}
+
+	w := csv.NewWriter(t.Writer)
+	w.Comma = comma
+	if len(rows) != 0 && !printed {
+		_ = w.Write(header)
+		if Stream {
+			t.rawHeader = header
+		}
+	}
+	for _, fields := range rows {
+		row := make([]string, len(header))
+		for _, f := range fields {
+			// the columns not in the header of the first batch are dropped in Stream mode
+			if i := slices.Index(header, f.Name); i != -1 {
+				row[i] = rawString(f.Value)
+			}
+		}
+		_ = w.Write(row)
+	}
+	w.Flush()
+	if !t.disableAfter {
+		fire(t.AfterPrint, t, s...)
+	}
+}
@@ -0,0 +1,28 @@
+package display
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestCSVPrinter_RawStream(t *testing.T) {
+	buf := &bytes.Buffer{}
+	Output = buf
+	Raw, Stream = true, true
+	defer func() {
+		Output = os.Stdout
+		Raw, Stream = false, false
+	}()
+
+	p := NewCSVPrinter().(*CSVPrinter)
+	p.Print(newJsonTestInfo(t, "a"), newJsonTestInfo(t, "b,c"))
+	p.Print(newJsonTestInfo(t, "d"))
+	p.ResetRawHeader()
+	p.Print(newJsonTestInfo(t, "e"))
+
+	want := "name\na\n\"b,c\"\nd\nname\ne\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -93,3 +93,29 @@ func NewTree(ops ...Option) *Tree {
 	}
 	return t
 }
+
+// Summary is the recursive file count, dir count and size of the descendants of a node
+type Summary struct {
+	Files, Dirs int
+	Size        int64
+}
+
+// Summarize computes the summary of each dir node bottom-up, f is called with the summary of each dir node.
+// the nodes with children are dirs, including the followed symlinked dirs
+func (n *Node) Summarize(f func(node *Node, s Summary)) Summary {
+	if len(n.Child) == 0 && !n.Meta.IsDir() {
+		return Summary{Files: 1, Size: n.Meta.Size()}
+	}
+	var s Summary
+	for _, child := range n.Child {
+		cs := child.Summarize(f)
+		if len(child.Child) != 0 || child.Meta.IsDir() {
+			s.Dirs++
+		}
+		s.Files += cs.Files
+		s.Dirs += cs.Dirs
+		s.Size += cs.Size
+	}
+	f(n, s)
+	return s
+}
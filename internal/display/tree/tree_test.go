@@ -0,0 +1,64 @@
+package tree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Equationzhao/g/internal/item"
+)
+
+func TestNode_Summarize(t *testing.T) {
+	dir := t.TempDir()
+	newNode := func(name string, size int) *Node {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		var err error
+		if size < 0 {
+			err = os.Mkdir(path, 0o755)
+		} else {
+			err = os.WriteFile(path, make([]byte, size), 0o644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := item.NewFileInfo(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &Node{Meta: info}
+	}
+	// root
+	// ├── a
+	// │   ├── b
+	// │   │   ╰── c.txt(10)
+	// │   ╰── d.txt(20)
+	// ├── empty
+	// ╰── e.txt(5)
+	root, a, b, empty := newNode("root", -1), newNode("a", -1), newNode("b", -1), newNode("empty", -1)
+	b.AddChild(newNode("c.txt", 10))
+	a.AddChild(b).AddChild(newNode("d.txt", 20))
+	root.AddChild(a).AddChild(empty).AddChild(newNode("e.txt", 5))
+
+	got := make(map[string]Summary)
+	s := root.Summarize(func(node *Node, s Summary) {
+		got[node.Meta.Name()] = s
+	})
+	want := map[string]Summary{
+		"root":  {Files: 3, Dirs: 3, Size: 35},
+		"a":     {Files: 2, Dirs: 1, Size: 30},
+		"b":     {Files: 1, Dirs: 0, Size: 10},
+		"empty": {},
+	}
+	if s != want["root"] {
+		t.Errorf("Summarize() = %+v, want %+v", s, want["root"])
+	}
+	if len(got) != len(want) {
+		t.Errorf("Summarize() called f with %d nodes, want %d", len(got), len(want))
+	}
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("summary of %s = %+v, want %+v", name, got[name], w)
+		}
+	}
+}
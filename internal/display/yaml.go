@@ -0,0 +1,38 @@
+package display
+
+import (
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NewYamlPrinter returns a JsonPrinter which outputs yaml
+func NewYamlPrinter() Printer {
+	p := NewJsonPrinter().(*JsonPrinter)
+	p.YAML = true
+	return p
+}
+
+// marshalYaml converts v to yaml through json, so the order of ordered maps
+// and the structured content(json.RawMessage) are kept
+func marshalYaml(v any) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	// json is a subset of yaml
+	var node yaml.Node
+	if err = yaml.Unmarshal(b, &node); err != nil {
+		return nil, err
+	}
+	resetYamlStyle(&node)
+	return yaml.Marshal(&node)
+}
+
+// resetYamlStyle removes the json styles(flow mappings, quoted strings) from the node
+func resetYamlStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		resetYamlStyle(n)
+	}
+}
@@ -2,8 +2,8 @@ package filter
 
 import (
 	"os"
-	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -32,6 +32,13 @@ func NewItemFilter(tfs ...*ItemFilterFunc) *ItemFilter {
 	return &ItemFilter{tfs: tfs}
 }
 
+// Without returns a copy of the filter without typeFunc
+func (tf *ItemFilter) Without(typeFunc *ItemFilterFunc) *ItemFilter {
+	return &ItemFilter{tfs: slices.DeleteFunc(slices.Clone(tf.tfs), func(f *ItemFilterFunc) bool {
+		return f == typeFunc
+	})}
+}
+
 func (tf *ItemFilter) Match(e *item.FileInfo) bool {
 	ok := keep
 	for _, funcPtr := range tf.tfs {
@@ -158,38 +165,15 @@ var RemoveBackups = func(e *item.FileInfo) bool {
 	return !strings.HasSuffix(e.Name(), "~")
 }
 
-func RemoveGitIgnore(repoPath git.RepoPath) ItemFilterFunc {
-	isOrIsParentOf := func(parent, child string) bool {
-		if parent == child {
-			return true
-		}
-		if strings.HasPrefix(child, parent+string(filepath.Separator)) { // should not use filepath.Separator
-			return true
-		}
-		return false
-	}
-	ignoredCache := git.GetCache()
-
-	return func(e *item.FileInfo) (ok bool) {
-		actual, _ := ignoredCache.GetOrCompute(repoPath, git.DefaultInit(repoPath))
-		ok = true
-		topLevel, err := git.GetTopLevel(repoPath)
-		if err != nil {
-			return keep
-		}
-		rel, err := filepath.Rel(topLevel, e.FullPath)
-		if err != nil {
-			return keep
-		}
-		for _, fileGit := range *actual {
-			if fileGit.X == git.Ignored || fileGit.Y == git.Ignored {
-				if isOrIsParentOf(fileGit.Name, rel) {
-					ok = remove
-					break
-				}
-			}
+// RemoveGitIgnore removes the files ignored by git,
+// the gitignore rules are parsed natively, so git is not required
+func RemoveGitIgnore() ItemFilterFunc {
+	ignore := git.NewIgnore()
+	return func(e *item.FileInfo) bool {
+		if ignore.Match(e.FullPath, e.IsDir()) {
+			return remove
 		}
-		return ok
+		return keep
 	}
 }
 
@@ -210,10 +194,11 @@ func MimeTypeOnly(fileTypes ...string) ItemFilterFunc {
 		if e.IsDir() {
 			return keep
 		}
-		file, err := os.Open(e.Name())
+		file, err := os.Open(e.FullPath)
 		if err != nil {
 			return keep
 		}
+		defer file.Close()
 		mtype, err := mimetype.DetectReader(file)
 		if err != nil {
 			return keep
@@ -235,10 +220,11 @@ func MimeTypeOnly(fileTypes ...string) ItemFilterFunc {
 
 func RemoveMimeType(fileTypes ...string) ItemFilterFunc {
 	return func(e *item.FileInfo) bool {
-		file, err := os.Open(e.Name())
+		file, err := os.Open(e.FullPath)
 		if err != nil {
 			return keep
 		}
+		defer file.Close()
 		mtype, err := mimetype.DetectReader(file)
 		if err != nil {
 			return keep
@@ -258,6 +244,26 @@ func RemoveMimeType(fileTypes ...string) ItemFilterFunc {
 	}
 }
 
+// SizeFunc returns the size of the entry to be compared by MinSize and MaxSize,
+// ok is false if the entry should not be filtered by size
+type SizeFunc = func(e *item.FileInfo) (size int64, ok bool)
+
+// MinSize keeps the entries whose size is larger than or equal to minSize
+func MinSize(minSize int64, sizeFunc SizeFunc) ItemFilterFunc {
+	return func(e *item.FileInfo) bool {
+		size, ok := sizeFunc(e)
+		return !ok || size >= minSize
+	}
+}
+
+// MaxSize keeps the entries whose size is smaller than or equal to maxSize
+func MaxSize(maxSize int64, sizeFunc SizeFunc) ItemFilterFunc {
+	return func(e *item.FileInfo) bool {
+		size, ok := sizeFunc(e)
+		return !ok || size <= maxSize
+	}
+}
+
 func BeforeTime(t time.Time, timeFunc func(os.FileInfo) time.Time) ItemFilterFunc {
 	return func(e *item.FileInfo) bool {
 		return timeFunc(e).Before(t)
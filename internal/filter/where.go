@@ -0,0 +1,452 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Equationzhao/g/internal/content"
+	"github.com/Equationzhao/g/internal/git"
+	"github.com/Equationzhao/g/internal/item"
+	"github.com/Equationzhao/g/internal/osbased"
+	"github.com/gabriel-vasile/mimetype"
+)
+
+/*
+Where compiles a boolean expression into an ItemFilterFunc, like
+
+	size > 10M && (ext == "go" || mime =~ "text/") && !hidden && mtime > -7d
+
+Grammar:
+
+	expr       = and { "||" and }
+	and        = unary { "&&" unary }
+	unary      = "!" unary | "(" expr ")" | comparison
+	comparison = field [ op value ]
+	op         = "==" | "!=" | "<" | "<=" | ">" | ">=" | "=~" | "!~"
+
+Fields:
+
+	name, path, ext, mime, git    string, compared with == != and the regexp operators =~ !~
+	                               ext has no leading dot, git is the XY status like "-M" or "??"
+	size                           number, the value can have a size unit like 10M, see --si
+	mtime, atime, ctime            time, the value is a duration relative to now like -7d, -12h (units: s m h d w y)
+	                               or a date like "2006-01-02" or "2006-01-02 15:04"
+	hidden, dir, file, link, exec  bool, can be used alone or compared with true/false
+*/
+func Where(expr string, isSI bool) (ItemFilterFunc, error) {
+	p := &whereParser{expr: expr, isSI: isSI, now: time.Now()}
+	if err := p.lex(); err != nil {
+		return nil, err
+	}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorAt(t.col, "unexpected %q", t.text)
+	}
+	return f, nil
+}
+
+// WhereError is the error of parsing the expression of Where, Col is the 1-based column of the offending token
+type WhereError struct {
+	Expr string
+	Col  int
+	Msg  string
+}
+
+func (e *WhereError) Error() string {
+	return fmt.Sprintf("%s at column %d\n\t%s\n\t%s^", e.Msg, e.Col, e.Expr, strings.Repeat(" ", e.Col-1))
+}
+
+type tokenKind uint8
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokValue // number, size or duration, like 10, 10M, -7d
+	tokOp
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	col  int
+}
+
+type whereParser struct {
+	expr   string
+	isSI   bool
+	now    time.Time
+	tokens []token
+	pos    int
+}
+
+func (p *whereParser) errorAt(col int, format string, a ...any) error {
+	return &WhereError{Expr: p.expr, Col: col, Msg: fmt.Sprintf(format, a...)}
+}
+
+func (p *whereParser) lex() error {
+	s := p.expr
+	for i := 0; i < len(s); {
+		c := s[i]
+		col := i + 1
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			p.tokens = append(p.tokens, token{tokLParen, "(", col})
+			i++
+		case c == ')':
+			p.tokens = append(p.tokens, token{tokRParen, ")", col})
+			i++
+		case strings.HasPrefix(s[i:], "&&"):
+			p.tokens = append(p.tokens, token{tokAnd, "&&", col})
+			i += 2
+		case strings.HasPrefix(s[i:], "||"):
+			p.tokens = append(p.tokens, token{tokOr, "||", col})
+			i += 2
+		case strings.HasPrefix(s[i:], "=="), strings.HasPrefix(s[i:], "!="), strings.HasPrefix(s[i:], "=~"),
+			strings.HasPrefix(s[i:], "!~"), strings.HasPrefix(s[i:], "<="), strings.HasPrefix(s[i:], ">="):
+			p.tokens = append(p.tokens, token{tokOp, s[i : i+2], col})
+			i += 2
+		case c == '<' || c == '>':
+			p.tokens = append(p.tokens, token{tokOp, s[i : i+1], col})
+			i++
+		case c == '!':
+			p.tokens = append(p.tokens, token{tokNot, "!", col})
+			i++
+		case c == '"' || c == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(s) && s[j] != c; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				b.WriteByte(s[j])
+			}
+			if j >= len(s) {
+				return p.errorAt(col, "unterminated string")
+			}
+			p.tokens = append(p.tokens, token{tokString, b.String(), col})
+			i = j + 1
+		case c == '-' || c == '+' || c == '.' || isDigit(c):
+			j := i + 1
+			for j < len(s) && (isDigit(s[j]) || s[j] == '.' || s[j] == ',' || isLetter(s[j])) {
+				j++
+			}
+			p.tokens = append(p.tokens, token{tokValue, s[i:j], col})
+			i = j
+		case isLetter(c) || c == '_':
+			j := i + 1
+			for j < len(s) && (isLetter(s[j]) || isDigit(s[j]) || s[j] == '_') {
+				j++
+			}
+			p.tokens = append(p.tokens, token{tokIdent, s[i:j], col})
+			i = j
+		default:
+			return p.errorAt(col, "unexpected character %q", c)
+		}
+	}
+	p.tokens = append(p.tokens, token{tokEOF, "end of expression", len(s) + 1})
+	return nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c < unicode.MaxASCII && unicode.IsLetter(rune(c))
+}
+
+func (p *whereParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *whereParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *whereParser) parseOr() (ItemFilterFunc, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(e *item.FileInfo) bool {
+			return l(e) || right(e)
+		}
+	}
+	return left, nil
+}
+
+func (p *whereParser) parseAnd() (ItemFilterFunc, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(e *item.FileInfo) bool {
+			return l(e) && right(e)
+		}
+	}
+	return left, nil
+}
+
+func (p *whereParser) parseUnary() (ItemFilterFunc, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNot:
+		f, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(e *item.FileInfo) bool {
+			return !f(e)
+		}, nil
+	case tokLParen:
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if r := p.next(); r.kind != tokRParen {
+			return nil, p.errorAt(r.col, "expected ')' to close '(' at column %d, got %q", t.col, r.text)
+		}
+		return f, nil
+	case tokIdent:
+		return p.parseComparison(t)
+	default:
+		return nil, p.errorAt(t.col, "expected a field, '!' or '(', got %q", t.text)
+	}
+}
+
+func (p *whereParser) parseComparison(field token) (ItemFilterFunc, error) {
+	f, ok := whereFields[strings.ToLower(field.text)]
+	if !ok {
+		return nil, p.errorAt(field.col, "unknown field %q", field.text)
+	}
+	if p.peek().kind != tokOp {
+		if f.boolean == nil {
+			return nil, p.errorAt(p.peek().col, "expected an operator after %s", field.text)
+		}
+		return f.boolean, nil
+	}
+	op := p.next()
+	value := p.next()
+	if value.kind != tokString && value.kind != tokValue && value.kind != tokIdent {
+		return nil, p.errorAt(value.col, "expected a value after %s, got %q", op.text, value.text)
+	}
+
+	switch {
+	case f.str != nil:
+		return p.compileString(f.str, op, value)
+	case f.num != nil:
+		return p.compileNumber(f.num, op, value)
+	case f.time != nil:
+		return p.compileTime(f.time, op, value)
+	default:
+		return p.compileBool(f.boolean, op, value)
+	}
+}
+
+func (p *whereParser) compileString(get func(e *item.FileInfo) string, op, value token) (ItemFilterFunc, error) {
+	switch op.text {
+	case "==":
+		return func(e *item.FileInfo) bool { return get(e) == value.text }, nil
+	case "!=":
+		return func(e *item.FileInfo) bool { return get(e) != value.text }, nil
+	case "=~", "!~":
+		re, err := regexp.Compile(value.text)
+		if err != nil {
+			return nil, p.errorAt(value.col, "invalid regexp: %s", err)
+		}
+		want := op.text == "=~"
+		return func(e *item.FileInfo) bool { return re.MatchString(get(e)) == want }, nil
+	default:
+		return nil, p.errorAt(op.col, "operator %s is not supported by string, use == != =~ !~", op.text)
+	}
+}
+
+func (p *whereParser) compileNumber(get func(e *item.FileInfo) int64, op, value token) (ItemFilterFunc, error) {
+	if op.text == "=~" || op.text == "!~" {
+		return nil, p.errorAt(op.col, "operator %s is not supported by number", op.text)
+	}
+	size, err := content.ParseSizeWithSI(value.text, p.isSI)
+	if err != nil {
+		return nil, p.errorAt(value.col, "invalid size %q: %s", value.text, err)
+	}
+	n := int64(size.Bytes)
+	cmp := compare(op.text)
+	return func(e *item.FileInfo) bool {
+		v := get(e)
+		switch {
+		case v < n:
+			return cmp(-1)
+		case v > n:
+			return cmp(1)
+		}
+		return cmp(0)
+	}, nil
+}
+
+func (p *whereParser) compileTime(get func(e *item.FileInfo) time.Time, op, value token) (ItemFilterFunc, error) {
+	if op.text == "=~" || op.text == "!~" {
+		return nil, p.errorAt(op.col, "operator %s is not supported by time", op.text)
+	}
+	var t time.Time
+	if value.kind == tokValue {
+		d, err := parseRelativeDuration(value.text)
+		if err != nil {
+			return nil, p.errorAt(value.col, "invalid duration %q: %s", value.text, err)
+		}
+		t = p.now.Add(d)
+	} else {
+		var err error
+		for _, layout := range []string{"2006-01-02", "2006-01-02 15:04", "2006-01-02 15:04:05", time.RFC3339} {
+			if t, err = time.ParseInLocation(layout, value.text, time.Local); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return nil, p.errorAt(value.col, "invalid time %q, use a duration like -7d or a date like 2006-01-02", value.text)
+		}
+	}
+	cmp := compare(op.text)
+	return func(e *item.FileInfo) bool {
+		return cmp(get(e).Compare(t))
+	}, nil
+}
+
+func (p *whereParser) compileBool(get ItemFilterFunc, op, value token) (ItemFilterFunc, error) {
+	want, err := strconv.ParseBool(value.text)
+	if err != nil || value.kind != tokIdent {
+		return nil, p.errorAt(value.col, "expected true or false, got %q", value.text)
+	}
+	switch op.text {
+	case "==":
+	case "!=":
+		want = !want
+	default:
+		return nil, p.errorAt(op.col, "operator %s is not supported by bool, use == !=", op.text)
+	}
+	return func(e *item.FileInfo) bool { return get(e) == want }, nil
+}
+
+// compare returns a function reporting whether the result of comparison satisfies the op
+func compare(op string) func(c int) bool {
+	switch op {
+	case "==":
+		return func(c int) bool { return c == 0 }
+	case "!=":
+		return func(c int) bool { return c != 0 }
+	case "<":
+		return func(c int) bool { return c < 0 }
+	case "<=":
+		return func(c int) bool { return c <= 0 }
+	case ">":
+		return func(c int) bool { return c > 0 }
+	default: // ">="
+		return func(c int) bool { return c >= 0 }
+	}
+}
+
+var durationUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+	"y": 365 * 24 * time.Hour,
+}
+
+// parseRelativeDuration parses duration like -7d, -1.5h, 30m
+func parseRelativeDuration(s string) (time.Duration, error) {
+	i := len(s)
+	for i > 0 && isLetter(s[i-1]) {
+		i--
+	}
+	n, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s[:i])
+	}
+	unit, ok := durationUnits[s[i:]]
+	if !ok {
+		return 0, fmt.Errorf("invalid unit %q, use s m h d w y", s[i:])
+	}
+	return time.Duration(n * float64(unit)), nil
+}
+
+type whereField struct {
+	str     func(e *item.FileInfo) string
+	num     func(e *item.FileInfo) int64
+	time    func(e *item.FileInfo) time.Time
+	boolean ItemFilterFunc
+}
+
+var whereFields = map[string]whereField{
+	"name": {str: func(e *item.FileInfo) string { return e.Name() }},
+	"path": {str: func(e *item.FileInfo) string { return e.FullPath }},
+	"ext":  {str: func(e *item.FileInfo) string { return strings.TrimPrefix(filepath.Ext(e.Name()), ".") }},
+	"mime": {str: mimeOf},
+	"git": {str: func(e *item.FileInfo) string {
+		x, y := git.StatusOf(e.FullPath)
+		return x.String() + y.String()
+	}},
+	"size":   {num: func(e *item.FileInfo) int64 { return e.Size() }},
+	"mtime":  {time: func(e *item.FileInfo) time.Time { return osbased.ModTime(e) }},
+	"atime":  {time: func(e *item.FileInfo) time.Time { return osbased.AccessTime(e) }},
+	"ctime":  {time: func(e *item.FileInfo) time.Time { return osbased.CreateTime(e) }},
+	"hidden": {boolean: HiddenOnly},
+	"dir":    {boolean: func(e *item.FileInfo) bool { return e.IsDir() }},
+	"file":   {boolean: func(e *item.FileInfo) bool { return e.Mode().IsRegular() }},
+	"link":   {boolean: func(e *item.FileInfo) bool { return e.Mode()&os.ModeSymlink != 0 }},
+	"exec": {boolean: func(e *item.FileInfo) bool {
+		return e.Mode().IsRegular() && e.Mode().Perm()&0o111 != 0
+	}},
+}
+
+// mimeOf returns the mime type without charset, dirs are "directory"
+func mimeOf(e *item.FileInfo) string {
+	if e.IsDir() {
+		return "directory"
+	}
+	if m, ok := e.Cache[MimeTypeName]; ok {
+		return string(m)
+	}
+	mtype, err := mimetype.DetectFile(e.FullPath)
+	if err != nil {
+		return ""
+	}
+	s, _, _ := strings.Cut(mtype.String(), ";")
+	e.Cache[MimeTypeName] = []byte(s)
+	return s
+}
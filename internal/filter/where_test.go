@@ -0,0 +1,97 @@
+package filter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Equationzhao/g/internal/item"
+)
+
+func TestWhere(t *testing.T) {
+	dir := t.TempDir()
+	newInfo := func(name string, size int, age time.Duration) *item.FileInfo {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mtime := time.Now().Add(-age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		info, err := item.NewFileInfo(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info
+	}
+	small := newInfo("main.go", 100, time.Hour)
+	big := newInfo("big.bin", 2<<20, 30*24*time.Hour)
+	hidden := newInfo(".env", 10, time.Hour)
+
+	tests := []struct {
+		expr string
+		want []bool // small, big, hidden
+	}{
+		{`size > 1M`, []bool{false, true, false}},
+		{`size <= 100`, []bool{true, false, true}},
+		{`ext == "go"`, []bool{true, false, false}},
+		{`ext != 'go'`, []bool{false, true, true}},
+		{`name =~ "^ma"`, []bool{true, false, false}},
+		{`name !~ "^ma"`, []bool{false, true, true}},
+		{`hidden`, []bool{false, false, true}},
+		{`!hidden && file`, []bool{true, true, false}},
+		{`hidden == false`, []bool{true, true, false}},
+		{`dir`, []bool{false, false, false}},
+		{`mtime > -7d`, []bool{true, false, true}},
+		{`mtime < -1w || ext == "go"`, []bool{true, true, false}},
+		{`size > 10M || (ext == "go" && mtime > -2h)`, []bool{true, false, false}},
+		{`!(size > 1M)`, []bool{true, false, true}},
+		{`mtime > "2000-01-01"`, []bool{true, true, true}},
+	}
+	infos := []*item.FileInfo{small, big, hidden}
+	for _, tt := range tests {
+		f, err := Where(tt.expr, false)
+		if err != nil {
+			t.Errorf("Where(%s) error = %v", tt.expr, err)
+			continue
+		}
+		for i, info := range infos {
+			if got := f(info); got != tt.want[i] {
+				t.Errorf("Where(%s)(%s) = %v, want %v", tt.expr, info.Name(), got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestWhere_Error(t *testing.T) {
+	tests := []struct {
+		expr string
+		col  int
+	}{
+		{`size >`, 7},
+		{`sizes > 1M`, 1},
+		{`size =~ "1"`, 6},
+		{`ext == "go" && (size > 1M`, 26},
+		{`ext < "go"`, 5},
+		{`mtime > -7x`, 9},
+		{`name == "unterminated`, 9},
+		{`hidden == yes`, 11},
+		{`size > 1M )`, 11},
+		{`size > 1M # comment`, 11},
+	}
+	for _, tt := range tests {
+		_, err := Where(tt.expr, false)
+		var we *WhereError
+		if !errors.As(err, &we) {
+			t.Errorf("Where(%s) error = %v, want WhereError", tt.expr, err)
+			continue
+		}
+		if we.Col != tt.col {
+			t.Errorf("Where(%s) error column = %d, want %d: %v", tt.expr, we.Col, tt.col, err)
+		}
+	}
+}
@@ -1,12 +1,15 @@
 package git
 
 import (
-	"encoding/json"
-	"fmt"
+	"bufio"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/Equationzhao/g/internal/cached"
+
 	strftime "github.com/itchyny/timefmt-go"
 )
 
@@ -41,30 +44,200 @@ func (c CommitInfo) GetAuthorDateInFormat(format string) string {
 
 var NoneCommitInfo = CommitInfo{"-", "-", "-", "-", "-"}
 
-// https://github.com/chaqchase/lla/blob/main/plugins/last_git_commit/src/lib.rs
+// GetLastCommitInfo returns the last commit changing the file or dir at path.
+// the history of each repository is read by a single 'git log' shared by all the paths in it, see commitLog
 func GetLastCommitInfo(path string) (*CommitInfo, error) {
-	return getLastCommitInfo(path)
+	topLevel, err := GetTopLevel(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	if topLevel == "" {
+		return &NoneCommitInfo, nil
+	}
+	// the top level from git has the symlinks resolved, so do the dirs of path, but not path itself
+	dir, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(topLevel, filepath.Join(dir, filepath.Base(path)))
+	if err != nil {
+		return nil, err
+	}
+	info := commitLogOf(topLevel).lastCommitOf(filepath.ToSlash(rel))
+	return &info, nil
 }
 
-const (
-	gitDateFormat = `format:"%Y-%m-%d %H:%M:%S.%9N %z"`
-	goParseFormat = time.RFC3339
+const goParseFormat = time.RFC3339
+
+var (
+	commitLogs         *cached.Map[RepoPath, *commitLog]
+	commitLogsInitOnce sync.Once
+	// commitLogsMu makes the entries computed once,
+	// GetOrCompute returns the value computed by each caller racing for the same key, not the one stored
+	commitLogsMu sync.Mutex
 )
 
-func getLastCommitInfo(path string) (*CommitInfo, error) {
-	cmd := exec.Command("git", "log", "-1", `--pretty=format:{"h":"%h","a":"%an","c":"%cn","ad":"%aI","cd":"%cI"}`, fmt.Sprintf(`--date=%s`, gitDateFormat), path)
+// commitLogOf returns the log of the repository, all the paths in it share the same one
+func commitLogOf(topLevel RepoPath) *commitLog {
+	if l, ok := getCommitLogs().Get(topLevel); ok {
+		return l
+	}
+	commitLogsMu.Lock()
+	defer commitLogsMu.Unlock()
+	l, _ := getCommitLogs().GetOrCompute(topLevel, func() *commitLog {
+		return newCommitLog(topLevel)
+	})
+	return l
+}
+
+// CloseCommitLogs stops reading the history of the repositories, see GetLastCommitInfo.
+// the commits read are kept, and the history is read again from the start if more paths are asked
+func CloseCommitLogs() {
+	for _, l := range getCommitLogs().Values() {
+		l.mu.Lock()
+		l.close()
+		l.mu.Unlock()
+	}
+}
+
+func getCommitLogs() *cached.Map[RepoPath, *commitLog] {
+	commitLogsInitOnce.Do(func() {
+		commitLogs = cached.NewCacheMap[RepoPath, *commitLog](size)
+		commitLogs.SetHasher(hasher)
+	})
+	return commitLogs
+}
+
+// commitLogArgs lists the files changed by each commit after its header,
+// the fields of the header are separated by \x1f, and the header starts with \x1e.
+// the merges list the files differing from all the parents, like 'git log -- <path>' shows them
+var commitLogArgs = []string{
+	"log", "-z", "--name-only", "--no-renames", "--diff-merges=dense-combined",
+	"--format=%x1e%h%x1f%an%x1f%cn%x1f%aI%x1f%cI",
+}
+
+// commitLog is the history of a repository read on demand.
+// the commits are read until all the paths asked are resolved,
+// and the paths in each commit not resolved yet, along with their dirs, take it as the last commit
+type commitLog struct {
+	mu       sync.Mutex
+	topLevel RepoPath
+	tracked  map[string]bool // the files in HEAD and their dirs, relative to the top level
+	commits  map[string]CommitInfo
+	current  CommitInfo
+	// done is set when the log is read to the end or all the paths are resolved
+	done bool
+	cmd  *exec.Cmd
+	r    *bufio.Reader
+}
+
+func newCommitLog(topLevel RepoPath) *commitLog {
+	l := &commitLog{topLevel: topLevel, tracked: make(map[string]bool), commits: make(map[string]CommitInfo)}
+	c := exec.Command("git", "ls-tree", "-r", "-z", "--name-only", "HEAD")
+	c.Dir = topLevel
+	out, err := c.Output()
+	if err != nil {
+		// no commit yet
+		return l
+	}
+	for _, name := range strings.Split(string(out), "\x00") {
+		for ; name != "" && !l.tracked[name]; name = parentOf(name) {
+			l.tracked[name] = true
+		}
+	}
+	if len(l.tracked) != 0 {
+		l.tracked["."] = true
+	}
+	return l
+}
+
+// start starts reading the log from the latest commit, it returns false if the log can't be read
+func (l *commitLog) start() bool {
+	l.cmd = exec.Command("git", commitLogArgs...)
+	l.cmd.Dir = l.topLevel
+	stdout, err := l.cmd.StdoutPipe()
+	if err != nil || l.cmd.Start() != nil {
+		l.cmd, l.done = nil, true
+		return false
+	}
+	l.r = bufio.NewReader(stdout)
+	return true
+}
+
+// parentOf returns the parent dir of the slash separated path, or "" for the top level ones
+func parentOf(name string) string {
+	i := strings.LastIndexByte(name, '/')
+	if i < 0 {
+		return ""
+	}
+	return name[:i]
+}
 
-	output, err := cmd.Output()
+// lastCommitOf returns the last commit of rel, a slash separated path relative to the top level
+func (l *commitLog) lastCommitOf(rel string) CommitInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for {
+		if info, ok := l.commits[rel]; ok {
+			return info
+		}
+		if !l.tracked[rel] || !l.next() {
+			return NoneCommitInfo
+		}
+	}
+}
+
+// next reads the next record of the log, it returns false when the log ends
+func (l *commitLog) next() bool {
+	if l.done || l.r == nil && !l.start() {
+		return false
+	}
+	record, err := l.r.ReadString(0)
 	if err != nil {
-		return nil, err
+		l.close()
+		l.done = true
+		return false
 	}
-	if len(output) == 0 {
-		return &NoneCommitInfo, nil
+	record = strings.TrimPrefix(strings.TrimSuffix(record, "\x00"), "\n")
+	if strings.HasPrefix(record, "\x1e") {
+		fields := strings.Split(record[1:], "\x1f")
+		if len(fields) == 5 {
+			l.current = CommitInfo{Hash: fields[0], Author: fields[1], Committer: fields[2], AuthorDate: fields[3], CommitterDate: fields[4]}
+		}
+		return true
 	}
-	var info CommitInfo
-	if err := json.Unmarshal(output, &info); err != nil {
-		return nil, err
+	if record == "" {
+		return true
+	}
+	for name := record; ; name = parentOf(name) {
+		if name == "" {
+			name = "."
+		}
+		if _, ok := l.commits[name]; ok {
+			// so are its dirs
+			break
+		}
+		if l.tracked[name] {
+			l.commits[name] = l.current
+		}
+		if name == "." {
+			break
+		}
+	}
+	if len(l.commits) == len(l.tracked) {
+		// every path is resolved, the rest of the log is not needed
+		l.close()
+		l.done = true
 	}
+	return true
+}
 
-	return &info, nil
+// close stops the git log, the next call of next starts it again
+func (l *commitLog) close() {
+	if l.cmd == nil {
+		return
+	}
+	_ = l.cmd.Process.Kill()
+	_ = l.cmd.Wait()
+	l.cmd, l.r = nil, nil
 }
@@ -0,0 +1,116 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGetLastCommitInfo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	// the path is not resolved, like /tmp -> /private/tmp on macOS
+	dir := t.TempDir()
+	run := func(args ...string) (string, error) {
+		c := exec.Command("git", args...)
+		c.Dir = dir
+		c.Env = append(os.Environ(), "GIT_AUTHOR_NAME=a", "GIT_AUTHOR_EMAIL=a@a", "GIT_COMMITTER_NAME=c", "GIT_COMMITTER_EMAIL=c@c")
+		out, err := c.Output()
+		return strings.TrimSpace(string(out)), err
+	}
+	git := func(args ...string) string {
+		t.Helper()
+		out, err := run(args...)
+		if err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+		return out
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	write("a/x.go", "1")
+	write("b/y.go", "1")
+	write("b/old.go", "1")
+	write("m/conflict.go", "1")
+	write("m/main.go", "1")
+	git("add", ".")
+	git("commit", "-q", "-m", "1")
+	write("a/x.go", "2")
+	git("commit", "-q", "-am", "2")
+	git("rm", "-q", "b/old.go")
+	git("commit", "-q", "-m", "3")
+
+	// a conflicting merge, resolved with new content
+	git("checkout", "-q", "-b", "side")
+	write("m/conflict.go", "side")
+	git("commit", "-q", "-am", "side")
+	git("checkout", "-q", "main")
+	write("m/conflict.go", "main")
+	write("m/main.go", "main")
+	git("commit", "-q", "-am", "main")
+	if _, err := run("merge", "-q", "side"); err == nil {
+		t.Fatal("git merge: want a conflict")
+	}
+	write("m/conflict.go", "resolved")
+	git("commit", "-q", "-am", "merge")
+	write("untracked.go", "")
+
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(dir, link); err != nil {
+		t.Fatal(err)
+	}
+	paths := []string{"a/x.go", "a", "b/y.go", "b", "m/conflict.go", "m/main.go", "m", "untracked.go", "missing.go"}
+	for _, root := range []string{dir, link} {
+		for _, p := range paths {
+			want := git("log", "-1", "--format=%h", "--", p)
+			if want == "" {
+				want = NoneCommitInfo.Hash
+			}
+			got, err := GetLastCommitInfo(filepath.Join(root, filepath.FromSlash(p)))
+			if err != nil {
+				t.Errorf("GetLastCommitInfo(%s) error = %v", p, err)
+				continue
+			}
+			if got.Hash != want {
+				t.Errorf("GetLastCommitInfo(%s) in %s = %s, want %s", p, root, got.Hash, want)
+			}
+			// the log is read again for the paths after
+			CloseCommitLogs()
+		}
+	}
+	if got, _ := GetLastCommitInfo(filepath.Join(dir, "a", "x.go")); got.Author != "a" || got.Committer != "c" {
+		t.Errorf("GetLastCommitInfo(a/x.go) = %+v, want author a and committer c", got)
+	}
+}
+
+func TestCommitLogOf(t *testing.T) {
+	dir := t.TempDir()
+	logs := make([]*commitLog, 8)
+	var wg sync.WaitGroup
+	for i := range logs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logs[i] = commitLogOf(dir)
+		}()
+	}
+	wg.Wait()
+	for _, l := range logs[1:] {
+		if l != logs[0] {
+			t.Fatal("commitLogOf() returns different logs for the same repository")
+		}
+	}
+}
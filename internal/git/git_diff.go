@@ -0,0 +1,123 @@
+package git
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Equationzhao/g/internal/cached"
+	"github.com/Equationzhao/g/internal/util"
+	"github.com/Equationzhao/pathbeautify"
+)
+
+// DiffStat is the number of lines added and deleted, like 'git diff --numstat'
+type DiffStat struct {
+	Added, Deleted int
+	// Binary is the number of binary files changed, whose lines are not counted
+	Binary int
+}
+
+// DiffStats is the diff stat of each file changed, named relative to the top level of the repository
+type DiffStats = map[string]DiffStat
+
+type DiffCache = *cached.Map[RepoPath, DiffStats]
+
+var (
+	// diffStats is the cache of the worktree and the one of the index
+	diffStats         [2]*cached.Map[RepoPath, DiffStats]
+	diffStatsInitOnce sync.Once
+)
+
+// GetDiffCache returns the cache of the diff stats of each repository, see DiffStatInit.
+// the stats of the worktree and the index are cached separately
+func GetDiffCache(staged bool) DiffCache {
+	diffStatsInitOnce.Do(func() {
+		for i := range diffStats {
+			diffStats[i] = cached.NewCacheMap[RepoPath, DiffStats](size)
+			diffStats[i].SetHasher(hasher)
+		}
+	})
+	if staged {
+		return diffStats[1]
+	}
+	return diffStats[0]
+}
+
+// DiffStatInit returns the diff stats of the repository of the worktree against HEAD,
+// or the index against HEAD if staged is set
+func DiffStatInit(repoPath RepoPath, staged bool) func() DiffStats {
+	return func() DiffStats {
+		args := []string{"diff", "--numstat", "-z"}
+		if staged {
+			args = append(args, "--cached")
+		}
+		c := exec.Command("git", append(args, "HEAD")...)
+		c.Dir = repoPath
+		out, err := c.Output()
+		if err != nil {
+			return DiffStats{}
+		}
+		return ParseNumstat(string(out))
+	}
+}
+
+// ParseNumstat parses the output of 'git diff --numstat -z', the records are like
+//
+//	<added>\t<deleted>\t<path>\0
+//	<added>\t<deleted>\t\0<orig>\0<path>\0    renamed or copied
+//
+// the binary files have '-' as added and deleted, and the invalid records are skipped
+func ParseNumstat(r string) DiffStats {
+	res := make(DiffStats)
+	records := strings.Split(r, "\x00")
+	for i := 0; i < len(records); i++ {
+		fields := strings.SplitN(records[i], "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		name := fields[2]
+		if name == "" {
+			// the new path is after the original one
+			if i+2 >= len(records) {
+				break
+			}
+			name = records[i+2]
+			i += 2
+		}
+		name = util.RemoveSep(pathbeautify.CleanSeparator(name))
+		if name == "" {
+			continue
+		}
+		var stat DiffStat
+		if fields[0] == "-" && fields[1] == "-" {
+			stat.Binary = 1
+		} else {
+			var err1, err2 error
+			stat.Added, err1 = strconv.Atoi(fields[0])
+			stat.Deleted, err2 = strconv.Atoi(fields[1])
+			if err1 != nil || err2 != nil || stat.Added < 0 || stat.Deleted < 0 {
+				continue
+			}
+		}
+		res[name] = stat
+	}
+	return res
+}
+
+// DiffStatIn returns the diff stat of rel, the path relative to the top level of the repository of stats,
+// the stats of a dir are summed from the files in it
+func DiffStatIn(stats DiffStats, rel string) DiffStat {
+	if stat, ok := stats[rel]; ok {
+		return stat
+	}
+	var sum DiffStat
+	for name, stat := range stats {
+		if within(name, rel) {
+			sum.Added += stat.Added
+			sum.Deleted += stat.Deleted
+			sum.Binary += stat.Binary
+		}
+	}
+	return sum
+}
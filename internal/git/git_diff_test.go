@@ -0,0 +1,119 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseNumstat(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want DiffStats
+	}{
+		{
+			name: "empty",
+			args: "",
+			want: DiffStats{},
+		},
+		{
+			name: "modified",
+			args: "12\t3\tmain.go\x000\t1\tinternal/a b.go\x00",
+			want: DiffStats{"main.go": {Added: 12, Deleted: 3}, "internal/a b.go": {Deleted: 1}},
+		},
+		{
+			name: "binary",
+			args: "-\t-\tlogo.png\x00",
+			want: DiffStats{"logo.png": {Binary: 1}},
+		},
+		{
+			name: "renamed",
+			args: "2\t2\t\x00old.go\x00new.go\x001\t0\tREADME.md\x00",
+			want: DiffStats{"new.go": {Added: 2, Deleted: 2}, "README.md": {Added: 1}},
+		},
+		{
+			name: "invalid",
+			args: "x\t1\ta\x00-1\t0\tb\x002\t2\x00",
+			want: DiffStats{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseNumstat(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseNumstat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffStatIn(t *testing.T) {
+	stats := DiffStats{
+		"main.go":           {Added: 1, Deleted: 2},
+		"internal/a.go":     {Added: 10, Deleted: 3},
+		"internal/b/c.go":   {Added: 2},
+		"internal/logo.png": {Binary: 1},
+		"internalx/d.go":    {Added: 100},
+	}
+	tests := []struct {
+		rel  string
+		want DiffStat
+	}{
+		{rel: "main.go", want: DiffStat{Added: 1, Deleted: 2}},
+		{rel: "internal", want: DiffStat{Added: 12, Deleted: 3, Binary: 1}},
+		{rel: "internal/b", want: DiffStat{Added: 2}},
+		{rel: ".", want: DiffStat{Added: 113, Deleted: 5, Binary: 1}},
+		{rel: "unchanged.go", want: DiffStat{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rel, func(t *testing.T) {
+			if got := DiffStatIn(stats, tt.rel); got != tt.want {
+				t.Errorf("DiffStatIn() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDiffCache(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	top := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		c := exec.Command("git", args...)
+		c.Dir = top
+		c.Env = append(os.Environ(), "GIT_AUTHOR_NAME=a", "GIT_AUTHOR_EMAIL=a@a", "GIT_COMMITTER_NAME=c", "GIT_COMMITTER_EMAIL=c@c")
+		if err := c.Run(); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(top, "a.txt"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q")
+	write("1\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "1")
+	// one line staged, and another one in the worktree only
+	write("1\n2\n")
+	git("add", ".")
+	write("1\n2\n3\n")
+
+	// the mode computed first must not be served for the other one
+	for _, staged := range []bool{true, false} {
+		want := DiffStat{Added: 2}
+		if staged {
+			want = DiffStat{Added: 1}
+		}
+		stats, _ := GetDiffCache(staged).GetOrCompute(top, DiffStatInit(top, staged))
+		if got := DiffStatIn(stats, "a.txt"); got != want {
+			t.Errorf("GetDiffCache(%v) = %+v, want %+v", staged, got, want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package git
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// the operations in progress in a repository, see RepoInfo.Operation
+const (
+	OperationMerge      = "merge"
+	OperationRebase     = "rebase"
+	OperationAm         = "am"
+	OperationCherryPick = "cherry-pick"
+	OperationRevert     = "revert"
+	OperationBisect     = "bisect"
+)
+
+// RepoInfo is the status of a repository, see GetRepoInfo
+type RepoInfo struct {
+	Status RepoStatus
+	// Upstream is the upstream branch like origin/main, empty if not set
+	Upstream string
+	// Ahead and Behind are the number of commits ahead of and behind Upstream
+	Ahead, Behind int
+	// Stash is the number of stash entries
+	Stash int
+	// Detached is the short hash of HEAD if it's detached, empty otherwise
+	Detached string
+	// Operation is the operation in progress like OperationMerge, empty if none
+	Operation string
+}
+
+// GetRepoInfo returns the status of the repository
+// only return the status when the path is the root of the repository
+func GetRepoInfo(repoPath RepoPath) RepoInfo {
+	if root, _ := GetTopLevel(repoPath); root != repoPath {
+		return RepoInfo{Status: RepoStatusSkip}
+	}
+
+	c := exec.Command("git", "status", "--porcelain=v2", "--branch", "-z")
+	c.Dir = repoPath
+	out, err := c.Output()
+	if err != nil {
+		return RepoInfo{Status: RepoStatusSkip}
+	}
+	info := ParseRepoInfo(string(out))
+
+	c = exec.Command("git", "rev-parse", "--absolute-git-dir", "--git-common-dir")
+	c.Dir = repoPath
+	out, err = c.Output()
+	if err != nil {
+		return info
+	}
+	dirs := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(dirs) != 2 {
+		return info
+	}
+	gitDir, commonDir := dirs[0], dirs[1]
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(repoPath, commonDir)
+	}
+	info.Operation = operationIn(gitDir)
+	info.Stash = stashCount(commonDir)
+	return info
+}
+
+// ParseRepoInfo parses the output of 'git status --porcelain=v2 --branch -z',
+// the repository is dirty if there is any entry besides the branch headers
+func ParseRepoInfo(r string) RepoInfo {
+	info := RepoInfo{Status: RepoStatusClean}
+	var oid string
+	var detached bool
+	for _, record := range strings.Split(r, "\x00") {
+		if record == "" {
+			continue
+		}
+		header, ok := strings.CutPrefix(record, "# ")
+		if !ok {
+			info.Status = RepoStatusDirty
+			continue
+		}
+		key, value, _ := strings.Cut(header, " ")
+		switch key {
+		case "branch.oid":
+			oid = value
+		case "branch.head":
+			detached = value == "(detached)"
+		case "branch.upstream":
+			info.Upstream = value
+		case "branch.ab":
+			ahead, behind, _ := strings.Cut(value, " ")
+			info.Ahead, _ = strconv.Atoi(strings.TrimPrefix(ahead, "+"))
+			info.Behind, _ = strconv.Atoi(strings.TrimPrefix(behind, "-"))
+		}
+	}
+	if detached {
+		info.Detached = oid[:min(len(oid), 7)]
+	}
+	return info
+}
+
+// operationIn returns the operation in progress by the state files in the git dir
+func operationIn(gitDir string) string {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(gitDir, name))
+		return err == nil
+	}
+	switch {
+	case exists("rebase-merge"):
+		return OperationRebase
+	case exists("rebase-apply"):
+		if exists(filepath.Join("rebase-apply", "applying")) {
+			return OperationAm
+		}
+		return OperationRebase
+	case exists("MERGE_HEAD"):
+		return OperationMerge
+	case exists("CHERRY_PICK_HEAD"):
+		return OperationCherryPick
+	case exists("REVERT_HEAD"):
+		return OperationRevert
+	case exists("BISECT_LOG"):
+		return OperationBisect
+	}
+	return ""
+}
+
+// stashCount returns the number of stash entries, which are the lines of the reflog of refs/stash
+func stashCount(commonDir string) int {
+	b, err := os.ReadFile(filepath.Join(commonDir, "logs", "refs", "stash"))
+	if err != nil {
+		return 0
+	}
+	return bytes.Count(b, []byte{'\n'})
+}
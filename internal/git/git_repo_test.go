@@ -0,0 +1,92 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRepoInfo(t *testing.T) {
+	const oid = "# branch.oid 01a3bf1c2d4e5f60718293a4b5c6d7e8f9012345\x00"
+	tests := []struct {
+		name string
+		args string
+		want RepoInfo
+	}{
+		{
+			name: "clean with upstream",
+			args: oid + "# branch.head main\x00# branch.upstream origin/main\x00# branch.ab +2 -1\x00",
+			want: RepoInfo{Status: RepoStatusClean, Upstream: "origin/main", Ahead: 2, Behind: 1},
+		},
+		{
+			name: "dirty without upstream",
+			args: oid + "# branch.head feature\x00? new file\x00",
+			want: RepoInfo{Status: RepoStatusDirty},
+		},
+		{
+			name: "detached",
+			args: oid + "# branch.head (detached)\x001 .M N... 100644 100644 100644 a b f\x00",
+			want: RepoInfo{Status: RepoStatusDirty, Detached: "01a3bf1"},
+		},
+		{
+			name: "initial",
+			args: "# branch.oid (initial)\x00# branch.head main\x00",
+			want: RepoInfo{Status: RepoStatusClean},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseRepoInfo(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRepoInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOperationIn(t *testing.T) {
+	tests := []struct {
+		files []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{"MERGE_HEAD"}, OperationMerge},
+		{[]string{"rebase-merge/done"}, OperationRebase},
+		{[]string{"rebase-apply/next"}, OperationRebase},
+		{[]string{"rebase-apply/applying"}, OperationAm},
+		{[]string{"CHERRY_PICK_HEAD"}, OperationCherryPick},
+		{[]string{"REVERT_HEAD"}, OperationRevert},
+		{[]string{"BISECT_LOG"}, OperationBisect},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		for _, f := range tt.files {
+			path := filepath.Join(dir, filepath.FromSlash(f))
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, nil, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := operationIn(dir); got != tt.want {
+			t.Errorf("operationIn(%v) = %q, want %q", tt.files, got, tt.want)
+		}
+	}
+}
+
+func TestStashCount(t *testing.T) {
+	dir := t.TempDir()
+	if got := stashCount(dir); got != 0 {
+		t.Errorf("stashCount() = %d, want 0", got)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "logs", "refs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "logs", "refs", "stash"), []byte("a b c\nd e f\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := stashCount(dir); got != 2 {
+		t.Errorf("stashCount() = %d, want 2", got)
+	}
+}
@@ -1,8 +1,9 @@
 package git
 
 import (
-	"bufio"
 	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/Equationzhao/g/internal/util"
@@ -14,6 +15,59 @@ import (
 type FileGit struct {
 	Name string
 	X, Y Status
+	// Sub is the state of the submodule, zero for the other entries
+	Sub SubmoduleStatus
+}
+
+// SubmoduleStatus is the state of a submodule in 'git status --porcelain=v2'
+type SubmoduleStatus uint8
+
+const (
+	// Submodule is set for all the submodules
+	Submodule SubmoduleStatus = 1 << iota
+	// SubmoduleCommitChanged means the commit of the submodule is changed
+	SubmoduleCommitChanged
+	// SubmoduleModified means the submodule has tracked changes
+	SubmoduleModified
+	// SubmoduleUntracked means the submodule has untracked files
+	SubmoduleUntracked
+)
+
+// parseSubmodule parses the <sub> field like 'N...' or 'S.M.'
+func parseSubmodule(sub string) SubmoduleStatus {
+	if len(sub) != 4 || sub[0] != 'S' {
+		return 0
+	}
+	s := Submodule
+	if sub[1] == 'C' {
+		s |= SubmoduleCommitChanged
+	}
+	if sub[2] == 'M' {
+		s |= SubmoduleModified
+	}
+	if sub[3] == 'U' {
+		s |= SubmoduleUntracked
+	}
+	return s
+}
+
+// String returns the state like the <sub> field of 'git status --porcelain=v2', like 'SC.U',
+// or "" if it's not a submodule
+func (s SubmoduleStatus) String() string {
+	if s&Submodule == 0 {
+		return ""
+	}
+	b := []byte("S...")
+	if s&SubmoduleCommitChanged != 0 {
+		b[1] = 'C'
+	}
+	if s&SubmoduleModified != 0 {
+		b[2] = 'M'
+	}
+	if s&SubmoduleUntracked != 0 {
+		b[3] = 'U'
+	}
+	return string(b)
 }
 
 /*
@@ -62,9 +116,10 @@ type FileGits = []FileGit
 
 type RepoPath = string
 
-// GetShortGitStatus read the git status of the repository located at the path
-func GetShortGitStatus(repoPath RepoPath) (string, error) {
-	c := exec.Command("git", "status", "-s", "--ignored", "--porcelain", repoPath)
+// GetGitStatus read the git status of the repository located at the path,
+// in the format of '--porcelain=v2 -z', see ParsePorcelainV2
+func GetGitStatus(repoPath RepoPath) (string, error) {
+	c := exec.Command("git", "status", "--porcelain=v2", "-z", "--ignored", repoPath)
 	c.Dir = repoPath
 	out, err := c.Output()
 	if err == nil {
@@ -89,43 +144,56 @@ const (
 	UpdatedButUnmerged        // U
 )
 
-// ParseShort parses a git status output command
-// It is compatible with the short version of the git status command
-// modified from https://le-gall.bzh/post/go/parsing-git-status-with-go/ author: Sébastien Le Gall
-func ParseShort(r string) (res FileGits) {
-	s := bufio.NewScanner(strings.NewReader(r))
-
-	for s.Scan() {
-		// Skip any empty line
-		if len(s.Text()) < 1 {
+// ParsePorcelainV2 parses the output of 'git status --porcelain=v2 -z'.
+// the records are separated by NUL, so the names are not quoted, and
+//
+//	1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>                  changed
+//	2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path>\0<orig> renamed or copied
+//	u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>        unmerged
+//	? <path>                                                      untracked
+//	! <path>                                                      ignored
+//
+// the renamed and copied entries are named by the new path, the headers and invalid records are skipped
+func ParsePorcelainV2(r string) (res FileGits) {
+	records := strings.Split(r, "\x00")
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if len(record) < 3 || record[1] != ' ' {
 			continue
 		}
-		break
-	}
-
-	fg := FileGit{}
-	for {
-		str := s.Text()
-		if len(str) < 1 {
+		var xy, sub, name string
+		switch record[0] {
+		case '1', '2', 'u':
+			n := 9
+			if record[0] == '2' {
+				n = 10
+			} else if record[0] == 'u' {
+				n = 11
+			}
+			fields := strings.SplitN(record, " ", n)
+			if len(fields) != n {
+				continue
+			}
+			xy, sub, name = fields[1], fields[2], fields[n-1]
+			if record[0] == '2' {
+				// the original path is the next record
+				i++
+			}
+		case '?':
+			xy, name = "??", record[2:]
+		case '!':
+			xy, name = "!!", record[2:]
+		default:
 			continue
 		}
-		status := str[0:2]
-		fg.Set(status)
-		if fg.X == Renamed || fg.Y == Renamed || fg.X == Copied || fg.Y == Copied {
-			// origin -> rename
-			// the actual file name is rename
-			o2r := str[3:]
-			fg.Name = util.RemoveSep(pathbeautify.CleanSeparator(o2r[strings.Index(o2r, " -> ")+4:]))
-		} else {
-			fg.Name = util.RemoveSep(pathbeautify.CleanSeparator(str[3:]))
+		name = util.RemoveSep(pathbeautify.CleanSeparator(name))
+		if len(xy) != 2 || name == "" {
+			continue
 		}
-
+		fg := FileGit{Name: name, Sub: parseSubmodule(sub)}
+		fg.Set(xy)
 		res = append(res, fg)
-		if !s.Scan() {
-			break
-		}
 	}
-
 	return res
 }
 
@@ -173,7 +241,7 @@ func Byte2Status(c byte) Status {
 		return Untracked
 	case '!':
 		return Ignored
-	case '-', ' ':
+	case '-', ' ', '.':
 		return Unmodified
 	case 'T':
 		return TypeChanged
@@ -221,21 +289,117 @@ func GetBranch(repoPath RepoPath) string {
 	return ""
 }
 
-// GetRepoStatus returns the status of the repository
-// only return the status when the path is the root of the repository
-func GetRepoStatus(repoPath RepoPath) RepoStatus {
-	if root, _ := GetTopLevel(repoPath); root != repoPath {
-		return RepoStatusSkip
+// StatusOf returns the git status of the file,
+// the status of its repository is read once and stored in GetCache.
+// x and y are Unmodified if the file is not changed or not in a repository
+func StatusOf(path string) (x, y Status) {
+	topLevel, err := GetTopLevel(filepath.Dir(path))
+	if err != nil || topLevel == "" {
+		return Unmodified, Unmodified
+	}
+	gits, _ := GetCache().GetOrCompute(topLevel, DefaultInit(topLevel))
+	rel, err := filepath.Rel(topLevel, path)
+	if err != nil {
+		return Unmodified, Unmodified
 	}
+	return StatusIn(*gits, rel)
+}
 
-	c := exec.Command("git", "status", "--porcelain")
-	c.Dir = repoPath
-	out, err := c.Output()
-	if err == nil {
-		if len(out) == 0 {
-			return RepoStatusClean
+// StatusIn returns the status of rel, the path relative to the top level of the repository of gits.
+// the entries in an untracked or ignored dir take its status,
+// and the status of a dir is rolled up from the changed entries in it, by the ones needing the most attention
+func StatusIn(gits FileGits, rel string) (x, y Status) {
+	x, y = Unmodified, Unmodified
+	for _, status := range gits {
+		ignored := status.X == Ignored || status.Y == Ignored
+		if (ignored || status.X == Untracked) && within(rel, status.Name) {
+			return status.X, status.Y
+		}
+		if ignored || !within(status.Name, rel) {
+			continue
 		}
-		return RepoStatusDirty
+		// the untracked entries are not in the index
+		if status.X != Untracked && status.X.attention() > x.attention() {
+			x = status.X
+		}
+		if status.Y.attention() > y.attention() {
+			y = status.Y
+		}
+	}
+	return x, y
+}
+
+// SubmoduleIn returns the state of the submodule at rel, the path relative to the top level of the repository of gits,
+// zero if rel is not a changed submodule
+func SubmoduleIn(gits FileGits, rel string) SubmoduleStatus {
+	for _, status := range gits {
+		if status.Name == rel {
+			return status.Sub
+		}
+	}
+	return 0
+}
+
+// StatusCount is the number of the changed entries with the status
+type StatusCount struct {
+	Status Status
+	Count  int
+}
+
+// CountIn returns the number of the changed entries in the dir rel by status, ordered by attention, see StatusIn.
+// each entry is counted once by its worktree status, or by its index status if it's unmodified in the worktree,
+// and the unmerged ones are counted as UpdatedButUnmerged. the ignored entries are not counted
+func CountIn(gits FileGits, rel string) []StatusCount {
+	counts := make(map[Status]int)
+	for _, status := range gits {
+		if status.X == Ignored || status.Y == Ignored || !within(status.Name, rel) {
+			continue
+		}
+		switch {
+		case status.X == UpdatedButUnmerged || status.Y == UpdatedButUnmerged:
+			counts[UpdatedButUnmerged]++
+		case status.Y != Unmodified:
+			counts[status.Y]++
+		case status.X != Unmodified:
+			counts[status.X]++
+		}
+	}
+	res := make([]StatusCount, 0, len(counts))
+	for status, count := range counts {
+		res = append(res, StatusCount{Status: status, Count: count})
+	}
+	slices.SortFunc(res, func(a, b StatusCount) int {
+		return b.Status.attention() - a.Status.attention()
+	})
+	return res
+}
+
+// within reports whether path is or is in the dir, "." is the top level
+func within(path, dir string) bool {
+	return dir == "." || path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// attention orders the statuses rolled up onto dirs, the higher the more attention is needed
+func (s Status) attention() int {
+	switch s {
+	case UpdatedButUnmerged:
+		return 9
+	case Modified:
+		return 8
+	case Deleted:
+		return 7
+	case Added:
+		return 6
+	case Renamed:
+		return 5
+	case Copied:
+		return 4
+	case TypeChanged:
+		return 3
+	case Untracked:
+		return 2
+	case Unknown:
+		return 1
 	}
-	return RepoStatusSkip
+	return 0
 }
@@ -7,72 +7,132 @@ import (
 	"testing"
 )
 
-func TestParseShort(t *testing.T) {
+func normalizePath(path string) string {
+	// normalize path according to the OS
+	switch os := runtime.GOOS; os {
+	case "windows":
+		return strings.ReplaceAll(path, "/", "\\")
+	default:
+		return strings.ReplaceAll(path, "\\", "/")
+	}
+}
+
+func TestParsePorcelainV2(t *testing.T) {
+	const h = "100644 100644 100644 975fbec8256d3e8a3797e7a3611380f27c49f4ac 975fbec8256d3e8a3797e7a3611380f27c49f4ac"
 	tests := []struct {
 		name    string
 		args    string
 		wantRes FileGits
 	}{
 		{
-			name: "case 1",
-			args: "AM internal/git/git_test.go\n!! .DS_Store\n!! .idea/\n!! build/\n",
+			name: "ordinary",
+			args: "1 .M N... " + h + " 中文.md\x001 A. N... " + h + " with space.txt\x00",
 			wantRes: FileGits{
-				{Name: "internal/git/git_test.go", X: Added, Y: Modified},
-				{Name: ".DS_Store", X: Ignored, Y: Ignored},
-				{Name: ".idea", X: Ignored, Y: Ignored},
-				{Name: "build", X: Ignored, Y: Ignored},
+				{Name: "中文.md", X: Unmodified, Y: Modified},
+				{Name: "with space.txt", X: Added, Y: Unmodified},
 			},
 		},
 		{
-			name: "case 2",
-			args: "D  my_folder/my_file.txt\n",
+			name: "renamed",
+			args: "2 R. N... " + h + " R100 new -> name\x00a -> b\x00? q\"uote\x00",
 			wantRes: FileGits{
-				{Name: "my_folder/my_file.txt", X: Deleted, Y: Unmodified},
+				{Name: "new -> name", X: Renamed, Y: Unmodified},
+				{Name: "q\"uote", X: Untracked, Y: Untracked},
 			},
 		},
 		{
-			name: "case 3",
-			args: " D my_folder/my_file.txt\n",
+			name: "unmerged",
+			args: "u UU N... 100644 100644 100644 100644 a b c conflict.go\x00",
 			wantRes: FileGits{
-				{Name: "my_folder/my_file.txt", X: Unmodified, Y: Deleted},
+				{Name: "conflict.go", X: UpdatedButUnmerged, Y: UpdatedButUnmerged},
 			},
 		},
 		{
-			name: "case 4",
-			args: "T  my_folder/my_file.txt\n",
+			name: "submodule",
+			args: "1 .M S.MU 160000 160000 160000 a b lib/sub\x00",
 			wantRes: FileGits{
-				{Name: "my_folder/my_file.txt", X: TypeChanged, Y: Unmodified},
+				{Name: "lib/sub", X: Unmodified, Y: Modified, Sub: Submodule | SubmoduleModified | SubmoduleUntracked},
 			},
 		},
 		{
-			name: "case 5",
-			args: "M  my_folder/file1.txt\nD  my_folder/file2.txt\nAU my_folder/file3.txt\n",
+			name: "ignored and headers",
+			args: "# branch.oid abc\x00# branch.head main\x00! build/\x00? line\nbreak\x00",
 			wantRes: FileGits{
-				{Name: "my_folder/file1.txt", X: Modified, Y: Unmodified},
-				{Name: "my_folder/file2.txt", X: Deleted, Y: Unmodified},
-				{Name: "my_folder/file3.txt", X: Added, Y: UpdatedButUnmerged},
+				{Name: "build", X: Ignored, Y: Ignored},
+				{Name: "line\nbreak", X: Untracked, Y: Untracked},
 			},
 		},
+		{
+			name:    "invalid",
+			args:    "1 .M N...\x002\x00?\x00x y\x00",
+			wantRes: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotRes := ParseShort(normalizePath(tt.args))
+			gotRes := ParsePorcelainV2(tt.args)
 			for i := range gotRes {
 				gotRes[i].Name = normalizePath(gotRes[i].Name)
 			}
+			for i := range tt.wantRes {
+				tt.wantRes[i].Name = normalizePath(tt.wantRes[i].Name)
+			}
 			if !reflect.DeepEqual(gotRes, tt.wantRes) {
-				t.Errorf("ParseShort() = %v, want %v", gotRes, tt.wantRes)
+				t.Errorf("ParsePorcelainV2() = %v, want %v", gotRes, tt.wantRes)
 			}
 		})
 	}
 }
 
-func normalizePath(path string) string {
-	// normalize path according to the OS
-	switch os := runtime.GOOS; os {
-	case "windows":
-		return strings.ReplaceAll(path, "/", "\\")
-	default:
-		return strings.ReplaceAll(path, "\\", "/")
+func FuzzParsePorcelainV2(f *testing.F) {
+	f.Add("1 .M N... 100644 100644 100644 a b file.go\x00")
+	f.Add("2 R. N... 100644 100644 100644 a b R100 new\x00old\x00")
+	f.Add("u UU N... 100644 100644 100644 100644 a b c file\x00? new\x00! ignored/\x00")
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, fg := range ParsePorcelainV2(s) {
+			if fg.Name == "" || strings.Contains(fg.Name, "\x00") {
+				t.Errorf("ParsePorcelainV2(%q) has name %q", s, fg.Name)
+			}
+			if fg.Sub != 0 && fg.Sub&Submodule == 0 {
+				t.Errorf("ParsePorcelainV2(%q) has submodule state %b without Submodule", s, fg.Sub)
+			}
+		}
+	})
+}
+
+func TestStatusIn(t *testing.T) {
+	gits := FileGits{
+		{Name: normalizePath("a/b/modified.go"), X: Unmodified, Y: Modified},
+		{Name: normalizePath("a/staged.go"), X: Added, Y: Unmodified},
+		{Name: normalizePath("a/new"), X: Untracked, Y: Untracked},
+		{Name: normalizePath("a/build"), X: Ignored, Y: Ignored},
+		{Name: normalizePath("c/conflict.go"), X: UpdatedButUnmerged, Y: UpdatedButUnmerged},
+	}
+	tests := []struct {
+		rel  string
+		x, y Status
+	}{
+		{"a", Added, Modified},
+		{"a/b", Unmodified, Modified},
+		{"a/b/modified.go", Unmodified, Modified},
+		{"a/new/file", Untracked, Untracked},
+		{"a/build/out", Ignored, Ignored},
+		{"c", UpdatedButUnmerged, UpdatedButUnmerged},
+		{".", UpdatedButUnmerged, UpdatedButUnmerged},
+		{"d", Unmodified, Unmodified},
+		{"ab", Unmodified, Unmodified},
+	}
+	for _, tt := range tests {
+		if x, y := StatusIn(gits, normalizePath(tt.rel)); x != tt.x || y != tt.y {
+			t.Errorf("StatusIn(%s) = %v%v, want %v%v", tt.rel, x, y, tt.x, tt.y)
+		}
+	}
+
+	want := []StatusCount{{UpdatedButUnmerged, 1}, {Modified, 1}, {Added, 1}, {Untracked, 1}}
+	if got := CountIn(gits, "."); !reflect.DeepEqual(got, want) {
+		t.Errorf("CountIn(.) = %v, want %v", got, want)
+	}
+	if got := CountIn(gits, "d"); len(got) != 0 {
+		t.Errorf("CountIn(d) = %v, want none", got)
 	}
 }
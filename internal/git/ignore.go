@@ -0,0 +1,490 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Equationzhao/g/internal/cached"
+)
+
+// ignoreRule is a pattern of gitignore
+// see https://git-scm.com/docs/gitignore#_pattern_format
+type ignoreRule struct {
+	segments []string // pattern split by '/', "**" matches zero or more segments
+	negate   bool     // !pattern
+	dirOnly  bool     // pattern/
+	anchored bool     // matched against the whole path relative to base, otherwise against the name
+	base     string   // the dir (relative to the top level, slash separated) where the rule is defined
+}
+
+// match reports whether the rule matches the path relative to the top level
+func (r *ignoreRule) match(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+		rel = rel[len(r.base)+1:]
+	}
+	if !r.anchored {
+		ok, _ := path.Match(r.segments[0], path.Base(rel))
+		return ok
+	}
+	return matchSegments(r.segments, strings.Split(rel, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				// a trailing "**" matches everything inside, but not the dir itself
+				return len(name) > 0
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// parseIgnoreRule parses a line of gitignore defined in base,
+// ok is false if the line is blank or a comment
+func parseIgnoreRule(line, base string) (rule ignoreRule, ok bool) {
+	line = strings.TrimSuffix(line, "\r")
+	// trailing spaces are ignored unless they are quoted with backslash
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	if line == "" || line[0] == '#' {
+		return rule, false
+	}
+	rule.base = base
+	if line[0] == '!' {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return rule, false
+	}
+	// a separator at the beginning or middle makes the pattern relative to the base
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	rule.segments = strings.Split(line, "/")
+	for i, segment := range rule.segments {
+		rule.segments[i] = negateClass(segment)
+	}
+	return rule, true
+}
+
+// negateClass translates the negated classes like [!a-z] to [^a-z], which path.Match supports
+func negateClass(pattern string) string {
+	if !strings.Contains(pattern, "[!") {
+		return pattern
+	}
+	b := []byte(pattern)
+	for i := 0; i < len(b); i++ {
+		switch b[i] {
+		case '\\':
+			i++
+		case '[':
+			if i+1 < len(b) && b[i+1] == '!' {
+				b[i+1] = '^'
+			}
+			// skip the class, ']' right after '[' or '[^' is a member of it
+			j := i + 1
+			if j < len(b) && b[j] == '^' {
+				j++
+			}
+			if j < len(b) && b[j] == ']' {
+				j++
+			}
+			for ; j < len(b) && b[j] != ']'; j++ {
+				if b[j] == '\\' {
+					j++
+				}
+			}
+			i = j
+		}
+	}
+	return string(b)
+}
+
+func readIgnoreFile(file, base string) []ignoreRule {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	rules := make([]ignoreRule, 0)
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if rule, ok := parseIgnoreRule(s.Text(), base); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Ignore matches paths with the gitignore rules, git is only run once per work tree to read core.excludesFile.
+// The rules are read from
+//
+//  1. core.excludesFile, default: $XDG_CONFIG_HOME/git/ignore
+//  2. $GIT_DIR/info/exclude
+//  3. .gitignore of every dir from the top level to the parent of the path
+//
+// the latter takes precedence, the last matched rule decides whether the path is ignored.
+// the paths in the index are never ignored, like git does.
+// The rules are parsed once and cached, Ignore is safe for concurrent use
+type Ignore struct {
+	topLevel *cached.Map[string, string]          // dir -> top level of the work tree, "" if not in a work tree
+	rules    *cached.Map[string, []ignoreRule]    // dir -> rules of the .gitignore in it
+	excludes *cached.Map[string, []ignoreRule]    // top level -> rules of core.excludesFile and info/exclude
+	tracked  *cached.Map[string, map[string]bool] // top level -> paths in the index and their dirs, see readIndex
+}
+
+func NewIgnore() *Ignore {
+	i := &Ignore{
+		topLevel: cached.NewCacheMap[string, string](size),
+		rules:    cached.NewCacheMap[string, []ignoreRule](size),
+		excludes: cached.NewCacheMap[string, []ignoreRule](size),
+		tracked:  cached.NewCacheMap[string, map[string]bool](size),
+	}
+	i.topLevel.SetHasher(hasher)
+	i.rules.SetHasher(hasher)
+	i.excludes.SetHasher(hasher)
+	i.tracked.SetHasher(hasher)
+	return i
+}
+
+// Match reports whether the path(absolute) is ignored by git.
+// a path inside an ignored dir is ignored as well
+func (i *Ignore) Match(absPath string, isDir bool) bool {
+	top := i.findTopLevel(filepath.Dir(absPath))
+	if top == "" {
+		return false
+	}
+	rel, err := filepath.Rel(top, absPath)
+	if err != nil || rel == "." {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == ".git" || strings.HasPrefix(rel, ".git/") {
+		return false
+	}
+
+	excludes, _ := i.excludes.GetOrCompute(top, func() []ignoreRule {
+		return readExcludes(top)
+	})
+	tracked, _ := i.tracked.GetOrCompute(top, func() map[string]bool {
+		gitDir, commonDir := findGitDir(top)
+		hashSize := 20
+		if format, _ := readConfigValue(filepath.Join(commonDir, "config"), "extensions", "objectformat"); strings.EqualFold(format, "sha256") {
+			hashSize = 32
+		}
+		return readIndex(filepath.Join(gitDir, "index"), hashSize)
+	})
+	// parent dirs are checked first, it's not possible to re-include a file if its parent dir is excluded
+	segments := strings.Split(rel, "/")
+	rules := excludes
+	// excluded is set once an ignored dir with tracked files is met, its untracked files are all ignored
+	excluded := false
+	for j := range segments {
+		dir := strings.Join(segments[:j], "/")
+		rules = append(rules[:len(rules):len(rules)], i.dirRules(top, dir)...)
+		sub := strings.Join(segments[:j+1], "/")
+		last := j == len(segments)-1
+		if tracked[sub] {
+			// the tracked files and the dirs with them are not ignored
+			excluded = excluded || i.ignored(rules, sub, !last || isDir)
+			continue
+		}
+		if excluded || i.ignored(rules, sub, !last || isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *Ignore) ignored(rules []ignoreRule, rel string, isDir bool) bool {
+	for j := len(rules) - 1; j >= 0; j-- {
+		if rules[j].match(rel, isDir) {
+			return !rules[j].negate
+		}
+	}
+	return false
+}
+
+// dirRules returns the rules of the .gitignore in the dir(relative to the top level)
+func (i *Ignore) dirRules(top, dir string) []ignoreRule {
+	abs := filepath.Join(top, filepath.FromSlash(dir))
+	rules, _ := i.rules.GetOrCompute(abs, func() []ignoreRule {
+		return readIgnoreFile(filepath.Join(abs, ".gitignore"), dir)
+	})
+	return rules
+}
+
+// findTopLevel returns the nearest ancestor(or itself) of the dir containing .git
+func (i *Ignore) findTopLevel(dir string) string {
+	top, _ := i.topLevel.GetOrCompute(dir, func() string {
+		if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		return i.findTopLevel(parent)
+	})
+	return top
+}
+
+// readExcludes reads core.excludesFile and info/exclude of the work tree
+func readExcludes(top string) []ignoreRule {
+	_, commonDir := findGitDir(top)
+	excludesFile := excludesFileOf(top, commonDir)
+	if excludesFile == "" {
+		if xdg := xdgConfigHome(); xdg != "" {
+			excludesFile = filepath.Join(xdg, "git", "ignore")
+		}
+	} else if strings.HasPrefix(excludesFile, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			excludesFile = filepath.Join(home, excludesFile[2:])
+		}
+	}
+
+	rules := make([]ignoreRule, 0)
+	if excludesFile != "" {
+		rules = append(rules, readIgnoreFile(excludesFile, "")...)
+	}
+	return append(rules, readIgnoreFile(filepath.Join(commonDir, "info", "exclude"), "")...)
+}
+
+// excludesFileOf returns core.excludesFile of the work tree, "" if it's not set.
+// it's resolved by 'git config' to honour every config level and the includes,
+// the config files are read without git if it's not installed
+func excludesFileOf(top, commonDir string) string {
+	c := exec.Command("git", "config", "--path", "core.excludesFile")
+	c.Dir = top
+	out, err := c.Output()
+	if err == nil {
+		return strings.TrimSpace(string(out))
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		// not set
+		return ""
+	}
+	excludesFile := ""
+	for _, config := range configFiles(commonDir) {
+		if v, ok := readConfigValue(config, "core", "excludesfile"); ok {
+			excludesFile = v
+		}
+	}
+	return excludesFile
+}
+
+// findGitDir returns the git dir of the work tree and the common dir shared by its worktrees,
+// .git can be a file like 'gitdir: path' for worktrees and submodules
+func findGitDir(top string) (gitDir, commonDir string) {
+	dotGit := filepath.Join(top, ".git")
+	stat, err := os.Stat(dotGit)
+	if err != nil || stat.IsDir() {
+		return dotGit, dotGit
+	}
+	content, err := os.ReadFile(dotGit)
+	if err != nil {
+		return dotGit, dotGit
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir:")
+	if !ok {
+		return dotGit, dotGit
+	}
+	gitDir = filepath.FromSlash(strings.TrimSpace(gitDir))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(top, gitDir)
+	}
+	// info/exclude and config of a worktree are in the common dir, but not its index
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = filepath.FromSlash(strings.TrimSpace(string(common)))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+		return gitDir, commonDir
+	}
+	return gitDir, gitDir
+}
+
+func xdgConfigHome() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return xdg
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".config")
+	}
+	return ""
+}
+
+// configFiles returns the git config files in the order of precedence, lowest first,
+// GIT_CONFIG_SYSTEM and GIT_CONFIG_GLOBAL replace the system and global ones like git does, empty for none
+func configFiles(gitDir string) []string {
+	files := make([]string, 0, 4)
+	if os.Getenv("GIT_CONFIG_NOSYSTEM") == "" {
+		if system, ok := os.LookupEnv("GIT_CONFIG_SYSTEM"); ok {
+			files = append(files, system)
+		} else {
+			files = append(files, "/etc/gitconfig")
+		}
+	}
+	if global, ok := os.LookupEnv("GIT_CONFIG_GLOBAL"); ok {
+		files = append(files, global)
+	} else {
+		if xdg := xdgConfigHome(); xdg != "" {
+			files = append(files, filepath.Join(xdg, "git", "config"))
+		}
+		if home, err := os.UserHomeDir(); err == nil {
+			files = append(files, filepath.Join(home, ".gitconfig"))
+		}
+	}
+	return append(files, filepath.Join(gitDir, "config"))
+}
+
+// readConfigValue reads the last value of section.key in the git config file,
+// section and key are case-insensitive, includes are not followed
+func readConfigValue(file, section, key string) (string, bool) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+	value, found := "", false
+	inSection := false
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			name, _, _ := strings.Cut(strings.Trim(line, "[]"), " ")
+			inSection = strings.EqualFold(strings.TrimSpace(name), section)
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(k), key) {
+			value, found = strings.Trim(strings.TrimSpace(v), `"`), true
+		}
+	}
+	return value, found
+}
+
+// readIndex returns the paths in the index and their dirs, slash separated and relative to the top level,
+// see https://git-scm.com/docs/index-format.
+// the index of version 2, 3 and 4 is read, nil is returned if it can't be read.
+// hashSize is the size of the object names, 20 for sha1 and 32 for sha256
+func readIndex(file string, hashSize int) map[string]bool {
+	data, err := os.ReadFile(file)
+	if err != nil || len(data) < 12 || string(data[:4]) != "DIRC" {
+		return nil
+	}
+	version := binary.BigEndian.Uint32(data[4:8])
+	if version < 2 || version > 4 {
+		return nil
+	}
+	count := binary.BigEndian.Uint32(data[8:12])
+	tracked := make(map[string]bool, count)
+	offset, name := 12, ""
+	for range count {
+		// ctime, mtime, dev, ino, mode, uid, gid, size, the object name and flags
+		start := offset
+		offset += 40 + hashSize
+		if offset+2 > len(data) {
+			return tracked
+		}
+		flags := binary.BigEndian.Uint16(data[offset:])
+		offset += 2
+		if version >= 3 && flags&0x4000 != 0 {
+			// extended flags
+			offset += 2
+		}
+		if offset > len(data) {
+			return tracked
+		}
+		if version == 4 {
+			// the name is the previous one without the last n bytes, followed by the rest
+			n, size := indexVarint(data[offset:])
+			if size == 0 || n > len(name) {
+				return tracked
+			}
+			offset += size
+			end := bytes.IndexByte(data[offset:], 0)
+			if end < 0 {
+				return tracked
+			}
+			name = name[:len(name)-n] + string(data[offset:offset+end])
+			offset += end + 1
+		} else {
+			end := bytes.IndexByte(data[offset:], 0)
+			if end < 0 {
+				return tracked
+			}
+			name = string(data[offset : offset+end])
+			// the entry is padded with 1-8 NULs to a multiple of 8 bytes
+			offset = start + (offset+end-start+8)&^7
+		}
+		// the dirs of a sparse index end with '/'
+		for n := strings.TrimSuffix(name, "/"); n != "" && !tracked[n]; n = parentOf(n) {
+			tracked[n] = true
+		}
+	}
+	return tracked
+}
+
+// indexVarint decodes the offset encoded varint of the index version 4,
+// size is 0 if it's invalid
+func indexVarint(b []byte) (value, size int) {
+	for i, c := range b {
+		if i > 8 {
+			return 0, 0
+		}
+		if i > 0 {
+			value++
+		}
+		value = value<<7 | int(c&0x7f)
+		if c&0x80 == 0 {
+			return value, i + 1
+		}
+	}
+	return 0, 0
+}
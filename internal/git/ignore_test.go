@@ -0,0 +1,170 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// isolateGitConfig makes git and the tests read no config but the ones under home
+func isolateGitConfig(t *testing.T, home string) {
+	t.Helper()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	// unset, an empty one means no global config
+	t.Setenv("GIT_CONFIG_GLOBAL", "")
+	if err := os.Unsetenv("GIT_CONFIG_GLOBAL"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIgnore_Match(t *testing.T) {
+	isolateGitConfig(t, t.TempDir())
+
+	top := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		p := filepath.Join(top, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".git/info/exclude", "*.exclude\n")
+	write(".gitignore", "# comment\n*.log\n!keep.log\nbuild/\n/root.txt\ndocs/**/*.tmp\nvendor/**\n!vendor/keep\n[!a]*.neg\n")
+	write("sub/.gitignore", "*.txt\n!important.txt\n")
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"a.log", false, true},
+		{"keep.log", false, false},
+		{"deep/dir/a.log", false, true},
+		{"build", true, true},
+		{"build", false, false},
+		{"build/output.bin", false, true},
+		{"root.txt", false, true},
+		{"sub/root.txt", false, true},
+		{"other/root.txt", false, false},
+		{"sub/important.txt", false, false},
+		{"docs/a.tmp", false, true},
+		{"docs/x/y/a.tmp", false, true},
+		{"a.tmp", false, false},
+		{"a.exclude", false, true},
+		// a trailing "**" matches the contents, not the dir, so they can be re-included
+		{"vendor", true, false},
+		{"vendor/lib.go", false, true},
+		{"vendor/keep", false, false},
+		{"b.neg", false, true},
+		{"a.neg", false, false},
+		{"main.go", false, false},
+		{".git", true, false},
+	}
+	ignore := NewIgnore()
+	for _, tt := range tests {
+		if got := ignore.Match(filepath.Join(top, filepath.FromSlash(tt.path)), tt.isDir); got != tt.want {
+			t.Errorf("Match(%s, isDir=%v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+
+	// not in a work tree
+	if ignore.Match(filepath.Join(t.TempDir(), "a.log"), false) {
+		t.Error("Match() outside of a work tree should return false")
+	}
+}
+
+func TestIgnore_ExcludesFile(t *testing.T) {
+	tests := []struct {
+		name string
+		// config is the file core.excludesFile is set in, relative to home
+		config string
+		noGit  bool
+	}{
+		{name: "global", config: ".gitconfig"},
+		{name: "system", config: "system"},
+		{name: "global without git", config: ".gitconfig", noGit: true},
+		{name: "system without git", config: "system", noGit: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			isolateGitConfig(t, home)
+			if tt.config == "system" {
+				t.Setenv("GIT_CONFIG_NOSYSTEM", "")
+				t.Setenv("GIT_CONFIG_SYSTEM", filepath.Join(home, "system"))
+			}
+			if tt.noGit {
+				t.Setenv("PATH", "")
+			}
+			if err := os.WriteFile(filepath.Join(home, tt.config), []byte("[core]\n\texcludesFile = ~/global_ignore\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(home, "global_ignore"), []byte(".DS_Store\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			top := t.TempDir()
+			if err := os.Mkdir(filepath.Join(top, ".git"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if !NewIgnore().Match(filepath.Join(top, "a", ".DS_Store"), false) {
+				t.Error("Match() should use core.excludesFile")
+			}
+		})
+	}
+}
+
+func TestIgnore_Tracked(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	isolateGitConfig(t, t.TempDir())
+	for _, version := range []string{"2", "3", "4"} {
+		t.Run("index version "+version, func(t *testing.T) {
+			top := t.TempDir()
+			git := func(args ...string) string {
+				t.Helper()
+				c := exec.Command("git", args...)
+				c.Dir = top
+				out, err := c.Output()
+				if err != nil {
+					t.Fatalf("git %v: %v", args, err)
+				}
+				return string(out)
+			}
+			for _, name := range []string{".gitignore", "build/tracked.bin", "build/untracked.bin", "a/b/c/tracked.log", "a/untracked.log"} {
+				p := filepath.Join(top, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				content := ""
+				if name == ".gitignore" {
+					content = "build/\n*.log\n"
+				}
+				if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			git("init", "-q")
+			git("add", ".gitignore")
+			git("add", "-f", "build/tracked.bin", "a/b/c/tracked.log")
+			git("update-index", "--index-version", version)
+
+			ignored := git("status", "--porcelain", "--ignored", "--untracked-files=all")
+			ignore := NewIgnore()
+			for _, name := range []string{"build", "build/tracked.bin", "build/untracked.bin", "a/b/c/tracked.log", "a/untracked.log"} {
+				want := strings.Contains(ignored, "!! "+name+"\n")
+				isDir := name == "build"
+				if got := ignore.Match(filepath.Join(top, filepath.FromSlash(name)), isDir); got != want {
+					t.Errorf("Match(%s) = %v, want %v like git status", name, got, want)
+				}
+			}
+		})
+	}
+}
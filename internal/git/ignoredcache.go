@@ -36,9 +36,9 @@ func GetCache() Cache {
 func DefaultInit(repoPath RepoPath) func() *FileGits {
 	return func() *FileGits {
 		res := make(FileGits, 0)
-		out, err := GetShortGitStatus(repoPath)
+		out, err := GetGitStatus(repoPath)
 		if err == nil && out != "" {
-			res = ParseShort(out)
+			res = ParsePorcelainV2(out)
 		}
 		return &res
 	}
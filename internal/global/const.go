@@ -68,6 +68,8 @@ const (
 	NameOfOwnerSID      = "Owner-sid"
 	NameOfSize          = "Size"
 	NameOfGitStatus     = "Git"
+	NameOfGitCount      = "Git-Count"
+	NameOfGitDiffStat   = "Git-Diffstat"
 	NameOfGitRepoBranch = "Branch"
 	NameOfGitRepoStatus = "Repo-status"
 	NameOfGitCommitHash = "Commit-Hash"
@@ -82,4 +84,5 @@ const (
 	NameOfTimeAccessed  = "Accessed"
 	NameOfTimeBirth     = "Birth"
 	NameOfFlags         = "Flags"
+	NameOfXattrs        = "Xattrs"
 )
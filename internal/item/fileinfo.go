@@ -1,6 +1,7 @@
 package item
 
 import (
+	"encoding/json"
 	"errors"
 	"os"
 	"path/filepath"
@@ -132,6 +133,58 @@ func (i *FileInfo) Set(key string, ic Item) {
 	i.Meta.Set(key, ic)
 }
 
+const (
+	jsonCachePrefix   = "json:"
+	fieldsCachePrefix = "fields:"
+)
+
+// SetJson sets the raw json of the content by key,
+// which is output by JsonPrinter instead of the string content
+func (i *FileInfo) SetJson(key string, raw []byte) {
+	i.Cache[jsonCachePrefix+key] = raw
+}
+
+// Json returns the raw json of the content by key
+func (i *FileInfo) Json(key string) ([]byte, bool) {
+	raw, ok := i.Cache[jsonCachePrefix+key]
+	return raw, ok
+}
+
+// Field is a typed machine-readable value, see FileInfo.SetFields
+type Field struct {
+	Name  string `json:"name"`
+	Value any    `json:"value"`
+}
+
+// SetFields splits the content by key into several typed fields in machine-readable(raw) output,
+// like git status into index and worktree status
+func (i *FileInfo) SetFields(key string, fields ...Field) {
+	raw, err := json.Marshal(fields)
+	if err == nil {
+		i.Cache[fieldsCachePrefix+key] = raw
+	}
+}
+
+// Fields returns the fields of the content by key set by SetFields,
+// the value of each field is raw json
+func (i *FileInfo) Fields(key string) ([]RawField, bool) {
+	raw, ok := i.Cache[fieldsCachePrefix+key]
+	if !ok {
+		return nil, false
+	}
+	var fields []RawField
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, false
+	}
+	return fields, true
+}
+
+// RawField is a Field with the value in raw json
+type RawField struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value"`
+}
+
 func (i *FileInfo) Values() []Item {
 	return i.Meta.Values()
 }
@@ -25,6 +25,20 @@ func Inode(info os.FileInfo) string {
 	return ""
 }
 
+// FileID returns the device and inode number of the file
+func FileID(info os.FileInfo) (dev, ino uint64, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if ok {
+		return uint64(stat.Dev), stat.Ino, true
+	}
+	return 0, 0, false
+}
+
+// ZeroSys returns an empty system stat, for the entries without metadata
+func ZeroSys() any {
+	return &syscall.Stat_t{}
+}
+
 func LinkCount(info os.FileInfo) uint64 {
 	stat, ok := info.Sys().(*syscall.Stat_t)
 	if ok {
@@ -15,6 +15,16 @@ func Inode(info os.FileInfo) string {
 	return "-"
 }
 
+// FileID is not supported on Windows
+func FileID(info os.FileInfo) (dev, ino uint64, ok bool) {
+	return 0, 0, false
+}
+
+// ZeroSys returns an empty system stat, for the entries without metadata
+func ZeroSys() any {
+	return &syscall.Win32FileAttributeData{}
+}
+
 var (
 	kernel32                   = syscall.NewLazyDLL("kernel32.dll")
 	getFileInformationByHandle = kernel32.NewProc("GetFileInformationByHandle")
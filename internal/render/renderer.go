@@ -353,6 +353,10 @@ func (rd *Renderer) Checksum(toRender string) string {
 	return rd.infoByName(toRender, "checksum")
 }
 
+func (rd *Renderer) Xattr(toRender string) string {
+	return rd.infoByName(toRender, "xattr")
+}
+
 func (rd *Renderer) ByName(toRender string) (s theme.Style, found bool) {
 	name := strings.ToLower(filepath.Base(toRender))
 	style, ok := rd.theme.Name[name]
@@ -509,6 +513,12 @@ func (rd *Renderer) GitUpdatedButUnmerged(s string) string {
 	return rd.gitByStatus(s, "git_updated_but_unmerged")
 }
 
+// GitSubmodule renders the state of a submodule, like SC.U,
+// the themes written before it have no git_submodule, so it is not required
+func (rd *Renderer) GitSubmodule(s string) string {
+	return rd.gitPart(s, "git_submodule")
+}
+
 func (rd *Renderer) GitRepoBranch(branch string) string {
 	var style theme.Style
 	switch branch {
@@ -549,6 +559,58 @@ func (rd *Renderer) GitRepoStatus(status git.RepoStatus) string {
 	return bb.String()
 }
 
+// GitRepoAhead renders the number of commits ahead of the upstream, like ↑2
+func (rd *Renderer) GitRepoAhead(n string) string {
+	return rd.gitPart(n, "git-repo-ahead")
+}
+
+// GitRepoBehind renders the number of commits behind the upstream, like ↓1
+func (rd *Renderer) GitRepoBehind(n string) string {
+	return rd.gitPart(n, "git-repo-behind")
+}
+
+// GitRepoStash renders the number of stash entries, like $3
+func (rd *Renderer) GitRepoStash(n string) string {
+	return rd.gitPart(n, "git-repo-stash")
+}
+
+// GitRepoDetached renders the short hash of the detached HEAD, like @1a2b3c4
+func (rd *Renderer) GitRepoDetached(hash string) string {
+	return rd.gitPart(hash, "git-repo-detached")
+}
+
+// GitRepoOperation renders the operation in progress, like MERGE
+func (rd *Renderer) GitRepoOperation(operation string) string {
+	return rd.gitPart(operation, "git-repo-operation")
+}
+
+// GitDiffAdded renders the number of lines added, like +12
+func (rd *Renderer) GitDiffAdded(n string) string {
+	return rd.gitPart(n, "git-diff-added")
+}
+
+// GitDiffDeleted renders the number of lines deleted, like −3
+func (rd *Renderer) GitDiffDeleted(n string) string {
+	return rd.gitPart(n, "git-diff-deleted")
+}
+
+// GitDiffBinary renders the marker of the binary files changed
+func (rd *Renderer) GitDiffBinary() string {
+	return rd.gitPart("", "git-diff-binary")
+}
+
+func (rd *Renderer) gitPart(s, key string) string {
+	style := rd.theme.Git[key]
+	bb := bytebufferpool.Get()
+	defer bytebufferpool.Put(bb)
+	_, _ = bb.WriteString(style.Color)
+	checkStyle(&style, bb)
+	_, _ = bb.WriteString(style.Icon)
+	_, _ = bb.WriteString(s)
+	_, _ = bb.WriteString(rd.Colorend())
+	return bb.String()
+}
+
 func (rd *Renderer) Inode(inode string) string {
 	return rd.infoByName(inode, "inode")
 }
@@ -577,6 +639,64 @@ func (rd *Renderer) Mounts(mounts string) string {
 	return bb.String()
 }
 
+// Loop renders the marker of a symlink looping to one of its ancestors, like ↻ (loop to ../x)
+func (rd *Renderer) Loop(target string) string {
+	bb := bytebufferpool.Get()
+	defer bytebufferpool.Put(bb)
+	style := rd.theme.Special["loop"]
+	_, _ = bb.WriteString(style.Color)
+	checkStyle(&style, bb)
+	_, _ = bb.WriteString(style.Icon)
+	_, _ = bb.WriteString("(loop to ")
+	_, _ = bb.WriteString(target)
+	_ = bb.WriteByte(')')
+	_, _ = bb.WriteString(rd.Colorend())
+	return bb.String()
+}
+
+// DirError renders the error of reading a dir in the tree, like [permission denied]
+func (rd *Renderer) DirError(reason string) string {
+	bb := bytebufferpool.Get()
+	defer bytebufferpool.Put(bb)
+	style := rd.theme.Special["dir-error"]
+	_, _ = bb.WriteString(style.Color)
+	checkStyle(&style, bb)
+	_, _ = bb.WriteString(style.Icon)
+	_ = bb.WriteByte('[')
+	_, _ = bb.WriteString(reason)
+	_ = bb.WriteByte(']')
+	_, _ = bb.WriteString(rd.Colorend())
+	return bb.String()
+}
+
+// DirSummary renders the summary of a dir in the tree, like (12 files, 3 dirs, 1.2 MiB)
+func (rd *Renderer) DirSummary(summary string) string {
+	bb := bytebufferpool.Get()
+	defer bytebufferpool.Put(bb)
+	style := rd.theme.Special["summary"]
+	_, _ = bb.WriteString(style.Color)
+	checkStyle(&style, bb)
+	_, _ = bb.WriteString(style.Icon)
+	_ = bb.WriteByte('(')
+	_, _ = bb.WriteString(summary)
+	_ = bb.WriteByte(')')
+	_, _ = bb.WriteString(rd.Colorend())
+	return bb.String()
+}
+
+// More renders the summary of the entries over --tree-limit, like … 49,982 more entries (3.1 GiB)
+func (rd *Renderer) More(summary string) string {
+	bb := bytebufferpool.Get()
+	defer bytebufferpool.Put(bb)
+	style := rd.theme.Special["more"]
+	_, _ = bb.WriteString(style.Color)
+	checkStyle(&style, bb)
+	_, _ = bb.WriteString(style.Icon)
+	_, _ = bb.WriteString(summary)
+	_, _ = bb.WriteString(rd.Colorend())
+	return bb.String()
+}
+
 func (rd *Renderer) Colorend() string {
 	return rd.theme.InfoTheme["reset"].Color
 }
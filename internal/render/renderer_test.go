@@ -0,0 +1,20 @@
+package render
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/Equationzhao/g/internal/theme"
+)
+
+func TestGitSubmoduleWithoutThemeKey(t *testing.T) {
+	all := theme.DefaultAll
+	all.Git = maps.Clone(theme.DefaultAll.Git)
+	delete(all.Git, "git_submodule")
+	rd := NewRenderer(&all)
+
+	want := "SC.U" + rd.Colorend()
+	if got := rd.GitSubmodule("SC.U"); got != want {
+		t.Errorf("GitSubmodule() = %q, want %q", got, want)
+	}
+}
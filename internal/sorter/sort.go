@@ -86,6 +86,40 @@ func BySizeAscend(a, b *item.FileInfo) int {
 	return cmp.Compare(a.Size(), b.Size())
 }
 
+// ByDirSizeDescend sorts by the size of files and the summarized size of dirs in the tree, see --dir-summary
+func ByDirSizeDescend(a, b *item.FileInfo) int {
+	return cmp.Compare(summaryOf(b, content.DirSizeName, b.Size()), summaryOf(a, content.DirSizeName, a.Size()))
+}
+
+func ByDirSizeAscend(a, b *item.FileInfo) int {
+	return cmp.Compare(summaryOf(a, content.DirSizeName, a.Size()), summaryOf(b, content.DirSizeName, b.Size()))
+}
+
+// ByFileCountDescend sorts by the summarized file count of dirs in the tree, files count as 1, see --dir-summary
+func ByFileCountDescend(a, b *item.FileInfo) int {
+	return cmp.Compare(summaryOf(b, content.DirFilesName, fileCount(b)), summaryOf(a, content.DirFilesName, fileCount(a)))
+}
+
+func ByFileCountAscend(a, b *item.FileInfo) int {
+	return cmp.Compare(summaryOf(a, content.DirFilesName, fileCount(a)), summaryOf(b, content.DirFilesName, fileCount(b)))
+}
+
+func fileCount(info *item.FileInfo) int64 {
+	if info.IsDir() {
+		return 0
+	}
+	return 1
+}
+
+// summaryOf returns the summarized value in the cache, or the fallback if the info isn't summarized
+func summaryOf(info *item.FileInfo, key string, fallback int64) int64 {
+	if v, ok := info.Cache[key]; ok {
+		n, _ := strconv.ParseInt(string(v), 10, 64)
+		return n
+	}
+	return fallback
+}
+
 func ByRecursiveSizeDescend(depth int) FileSortFunc {
 	return func(a, b *item.FileInfo) int {
 		return byRecursiveSize(a, b, depth, false)
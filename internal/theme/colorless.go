@@ -1,5 +1,7 @@
 package theme
 
+import "maps"
+
 func SetClassic() {
 	DefaultAll.Apply(setClassic)
 }
@@ -17,3 +19,22 @@ func setClassic(m Theme) {
 		}
 	}
 }
+
+// Classic returns a copy of the theme with all colors and effects removed,
+// icons are kept
+func (a *All) Classic() *All {
+	c := &All{
+		InfoTheme:  maps.Clone(a.InfoTheme),
+		Permission: maps.Clone(a.Permission),
+		Size:       maps.Clone(a.Size),
+		User:       maps.Clone(a.User),
+		Group:      maps.Clone(a.Group),
+		Symlink:    maps.Clone(a.Symlink),
+		Git:        maps.Clone(a.Git),
+		Name:       maps.Clone(a.Name),
+		Special:    maps.Clone(a.Special),
+		Ext:        maps.Clone(a.Ext),
+	}
+	c.Apply(setClassic)
+	return c
+}
@@ -28,3 +28,20 @@ func TestSetClassic(t *testing.T) {
 	}
 	DefaultAll.Apply(checker)
 }
+
+func TestAll_Classic(t *testing.T) {
+	a := All{
+		InfoTheme: Theme{"time": {Color: "\033[0;34m", Bold: true}},
+		Name:      Theme{"go": {Color: "\033[0;34m", Icon: "go"}},
+	}
+	c := a.Classic()
+	if c.InfoTheme["time"].Color != "" || c.InfoTheme["time"].Bold {
+		t.Errorf("Classic() failed, got %v", c.InfoTheme["time"])
+	}
+	if c.Name["go"].Icon != "go" {
+		t.Errorf("Classic() should keep icon, got %v", c.Name["go"].Icon)
+	}
+	if a.InfoTheme["time"].Color == "" {
+		t.Errorf("Classic() should not modify the origin theme")
+	}
+}
@@ -164,6 +164,9 @@ var InfoTheme = Theme{
 	"checksum": {
 		Underline: true,
 	},
+	"xattr": {
+		Color: global.White,
+	},
 }
 
 var Ext = Theme{
@@ -1140,6 +1143,20 @@ var Special = map[string]Style{
 	"mounts": {
 		Color: global.BrightBlack,
 	},
+	"loop": {
+		Color: global.Yellow,
+		Icon:  "↻ ",
+	},
+	"dir-error": {
+		Color: global.Red,
+	},
+	"summary": {
+		Color: global.BrightBlack,
+	},
+	"more": {
+		Color: global.BrightBlack,
+		Icon:  "… ",
+	},
 }
 
 var Name = map[string]Style{
@@ -1681,6 +1698,9 @@ var Git = map[string]Style{
 	"git_updated_but_unmerged": {
 		Color: global.BrightYellow,
 	},
+	"git_submodule": {
+		Color: global.Cyan,
+	},
 	"git-repo-skip": {
 		Color: global.BrightBlack,
 		Icon:  "-",
@@ -1693,6 +1713,38 @@ var Git = map[string]Style{
 		Color: global.Yellow,
 		Icon:  "dirty",
 	},
+	"git-diff-added": {
+		Color: global.Green,
+		Icon:  "+",
+	},
+	"git-diff-deleted": {
+		Color: global.Red,
+		Icon:  "−",
+	},
+	"git-diff-binary": {
+		Color: global.BrightBlack,
+		Icon:  "bin",
+	},
+	"git-repo-ahead": {
+		Color: global.Green,
+		Icon:  "↑",
+	},
+	"git-repo-behind": {
+		Color: global.Red,
+		Icon:  "↓",
+	},
+	"git-repo-stash": {
+		Color: global.Purple,
+		Icon:  "$",
+	},
+	"git-repo-detached": {
+		Color: global.BrightYellow,
+		Icon:  "@",
+	},
+	"git-repo-operation": {
+		Color: global.Red,
+		Bold:  true,
+	},
 	"git-branch-master": { // master and main
 		Color: global.Green,
 		Bold:  true,
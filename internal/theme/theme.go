@@ -290,16 +290,28 @@ func (e ErrOpenTheme) Error() string {
 }
 
 func GetTheme(path string) error {
+	a, err := ReadTheme(path)
+	var errOpen ErrOpenTheme
+	if errors.As(err, &errOpen) {
+		return err
+	}
+	DefaultAll = a
+	return err
+}
+
+// ReadTheme reads the theme at path without replacing DefaultAll
+// ErrOpenTheme is returned if the theme can't be loaded,
+// otherwise the theme is returned along with the errors of bad colors
+func ReadTheme(path string) (All, error) {
 	themeJson, err := os.ReadFile(path)
 	if err != nil {
-		return ErrOpenTheme{err}
+		return All{}, ErrOpenTheme{err}
 	}
 	a, err, fatal := getTheme(themeJson)
 	if fatal != nil {
-		return ErrOpenTheme{fatal}
+		return All{}, ErrOpenTheme{fatal}
 	}
-	DefaultAll = a
-	return err
+	return a, err
 }
 
 func getTheme(themeJson []byte) (theme All, errSum, fatal error) {
@@ -118,3 +118,9 @@ func (m *MockFileInfo) Name() string {
 func (m *MockFileInfo) Sys() any {
 	return nil
 }
+
+// IsTerminal reports whether the file is a terminal(character device)
+func IsTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
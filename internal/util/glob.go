@@ -0,0 +1,114 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// HasGlobMeta reports whether the pattern contains any of the glob metacharacters *?[{
+func HasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[{")
+}
+
+// Glob returns the sorted paths matching the pattern, which is split by '/'.
+// each part is matched like glob(7) with {a,b} alternatives, and '**' matches zero or more dirs.
+// keep is called with the path and the part it matched for every candidate from a wildcard part,
+// the rejected dirs are not descended. the unreadable dirs and symlinked dirs under '**' are skipped
+func Glob(pattern string, keep func(path, part string) bool) ([]string, error) {
+	if filepath.Separator != '/' {
+		pattern = filepath.ToSlash(pattern)
+	}
+	var base string
+	if vol := filepath.VolumeName(pattern); vol != "" {
+		base, pattern = vol, pattern[len(vol):]
+	}
+	if strings.HasPrefix(pattern, "/") {
+		base += string(filepath.Separator)
+	}
+	var parts []string
+	for _, part := range strings.Split(pattern, "/") {
+		// a/**/**/b is the same as a/**/b
+		if part == "" || part == "." || part == "**" && len(parts) > 0 && parts[len(parts)-1] == "**" {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	g := &globber{keep: keep, seen: make(map[string]bool), matchers: make(map[string]glob.Glob)}
+	for _, part := range parts {
+		if part != "**" && HasGlobMeta(part) {
+			m, err := glob.Compile(part)
+			if err != nil {
+				return nil, err
+			}
+			g.matchers[part] = m
+		}
+	}
+	g.walk(base, parts)
+	slices.Sort(g.matches)
+	return g.matches, nil
+}
+
+type globber struct {
+	keep     func(path, part string) bool
+	matchers map[string]glob.Glob
+	seen     map[string]bool
+	matches  []string
+}
+
+func (g *globber) walk(dir string, parts []string) {
+	if len(parts) == 0 {
+		if dir != "" && !g.seen[dir] {
+			g.seen[dir] = true
+			g.matches = append(g.matches, dir)
+		}
+		return
+	}
+	part, rest := parts[0], parts[1:]
+	m, wildcard := g.matchers[part]
+	if !wildcard && part != "**" {
+		next := filepath.Join(dir, part)
+		if len(rest) == 0 {
+			if _, err := os.Lstat(next); err == nil {
+				g.walk(next, rest)
+			}
+		} else if stat, err := os.Stat(next); err == nil && stat.IsDir() {
+			g.walk(next, rest)
+		}
+		return
+	}
+
+	read := dir
+	if read == "" {
+		read = "."
+	}
+	entries, err := os.ReadDir(read)
+	if err != nil {
+		return
+	}
+	if part == "**" {
+		g.walk(dir, rest)
+	}
+	for _, entry := range entries {
+		next := filepath.Join(dir, entry.Name())
+		switch {
+		case part == "**":
+			if entry.IsDir() && g.keep(next, part) {
+				g.walk(next, parts)
+			} else if len(rest) == 0 && !entry.IsDir() && g.keep(next, part) {
+				// a trailing '**' matches the files too
+				g.walk(next, rest)
+			}
+		case !m.Match(entry.Name()) || !g.keep(next, part):
+		case len(rest) == 0:
+			g.walk(next, rest)
+		default:
+			if stat, err := os.Stat(next); err == nil && stat.IsDir() {
+				g.walk(next, rest)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"main.go", "main_test.go", "README.md", ".env",
+		"a/a_test.go", "a/b/b_test.go", "a/b/c.txt", ".hidden/h_test.go",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+
+	all := func(path, part string) bool { return true }
+	noHidden := func(path, part string) bool {
+		return strings.HasPrefix(part, ".") || !strings.HasPrefix(filepath.Base(path), ".")
+	}
+	tests := []struct {
+		pattern string
+		keep    func(path, part string) bool
+		want    []string
+	}{
+		{"*.go", all, []string{"main.go", "main_test.go"}},
+		{"**/*_test.go", all, []string{".hidden/h_test.go", "a/a_test.go", "a/b/b_test.go", "main_test.go"}},
+		{"**/*_test.go", noHidden, []string{"a/a_test.go", "a/b/b_test.go", "main_test.go"}},
+		{"a/**", all, []string{"a", "a/a_test.go", "a/b", "a/b/b_test.go", "a/b/c.txt"}},
+		{"a/**/**/c.txt", all, []string{"a/b/c.txt"}},
+		{"./a/*/*.{txt,md}", all, []string{"a/b/c.txt"}},
+		{"*.{md,go}", all, []string{"README.md", "main.go", "main_test.go"}},
+		{"?ain.go", all, []string{"main.go"}},
+		{"[ab]/*.go", all, []string{"a/a_test.go"}},
+		{".*", noHidden, []string{".env", ".hidden"}},
+		{"none/*.go", all, nil},
+		{filepath.ToSlash(dir) + "/a/*.go", all, []string{filepath.Join(dir, "a", "a_test.go")}},
+	}
+	for _, tt := range tests {
+		got, err := Glob(tt.pattern, tt.keep)
+		if err != nil {
+			t.Errorf("Glob(%s) error = %v", tt.pattern, err)
+			continue
+		}
+		want := make([]string, len(tt.want))
+		for i, w := range tt.want {
+			want[i] = filepath.FromSlash(w)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("Glob(%s) = %v, want %v", tt.pattern, got, want)
+		}
+	}
+
+	if _, err := Glob("[a", all); err == nil {
+		t.Error("Glob([a) error = nil, want error")
+	}
+}
+
+func TestHasGlobMeta(t *testing.T) {
+	for s, want := range map[string]bool{"a.go": false, "*.go": true, "a?": true, "[ab]": true, "{a,b}": true, "a/b": false} {
+		if got := HasGlobMeta(s); got != want {
+			t.Errorf("HasGlobMeta(%s) = %v, want %v", s, got, want)
+		}
+	}
+}
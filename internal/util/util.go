@@ -65,3 +65,21 @@ func SplitNumberAndUnit(input string) (float64, string) {
 
 	return number, unit
 }
+
+// Comma formats the integer with thousands separators, eg: 49982 -> "49,982"
+func Comma(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
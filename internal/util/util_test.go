@@ -151,3 +151,22 @@ func TestEscape(t *testing.T) {
 		})
 	}
 }
+
+func TestComma(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1,000"},
+		{49982, "49,982"},
+		{1234567, "1,234,567"},
+		{-1234, "-1,234"},
+	}
+	for _, tt := range tests {
+		if got := Comma(tt.n); got != tt.want {
+			t.Errorf("Comma(%d) = %s, want %s", tt.n, got, tt.want)
+		}
+	}
+}
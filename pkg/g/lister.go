@@ -0,0 +1,260 @@
+// Package g exposes the listing engine of g as a library.
+//
+// A Lister is built from options, it either returns the listed entries
+// with their columns filled, or renders them to any io.Writer using one of g's printers:
+//
+//	l, err := g.NewLister(g.WithLong(), g.WithIcon(), g.WithFormat(g.FormatByline))
+//	if err != nil {
+//		// handle the error
+//	}
+//	_ = l.Render(os.Stdout, ".")
+package g
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Equationzhao/g/internal/content"
+	"github.com/Equationzhao/g/internal/display"
+	"github.com/Equationzhao/g/internal/filter"
+	"github.com/Equationzhao/g/internal/item"
+	"github.com/Equationzhao/g/internal/render"
+	"github.com/Equationzhao/g/internal/sorter"
+	"github.com/Equationzhao/g/internal/theme"
+)
+
+type (
+	// FileInfo is a listed entry, the columns are stored in FileInfo.Meta,
+	// use FileInfo.Get with the column name (like "Name", "Size", "Permissions") to read them
+	FileInfo = item.FileInfo
+	// Theme is the theme used to render the columns
+	Theme = theme.All
+	// FilterFunc returns true to keep the entry
+	FilterFunc = filter.ItemFilterFunc
+	// SortFunc compares two entries, like the cmp function of slices.SortFunc
+	SortFunc = sorter.FileSortFunc
+)
+
+// Format decides which printer is used by Lister.Render
+type Format uint8
+
+const (
+	FormatGrid Format = iota
+	FormatAcross
+	FormatByline
+	FormatComma
+	FormatZero
+	FormatJSON
+	FormatTable
+	FormatMarkdown
+	FormatCSV
+	FormatTSV
+)
+
+// column makes the content option of a column with the renderer of the Lister
+type column = func(l *Lister, r *render.Renderer) content.ContentOption
+
+// Lister lists paths with the columns set by the options.
+// it is not changed by List or Render, each call works on its own copy of the columns,
+// so a Lister can be shared by goroutines
+type Lister struct {
+	showHidden bool
+	directory  bool
+	limit      uint
+	format     Format
+	theme      *Theme
+	colorless  bool
+	timeFormat string
+	filters    []*FilterFunc
+	sorts      []SortFunc
+	noSort     bool
+	reverse    bool
+	dirFirst   bool
+	columns    []column
+	name       *content.Name
+	size       *content.SizeEnabler
+	owner      *content.OwnerEnabler
+	group      *content.GroupEnabler
+	git        *content.GitEnabler // nil unless WithGit is set
+}
+
+// NewLister returns a Lister built from the options,
+// the errors of the options are joined and returned with the Lister
+func NewLister(opts ...Option) (*Lister, error) {
+	l := &Lister{
+		format:     FormatGrid,
+		theme:      &theme.DefaultAll,
+		timeFormat: "Jan 02 15:04",
+		name:       content.NewNameEnabler(),
+		size:       content.NewSizeEnabler(),
+		owner:      content.NewOwnerEnabler(),
+		group:      content.NewGroupEnabler(),
+	}
+	l.name.SetQuoteString(`'`)
+	var errSum error
+	for _, opt := range opts {
+		if err := opt(l); err != nil {
+			errSum = errors.Join(errSum, err)
+		}
+	}
+	if l.format == FormatJSON {
+		l.name.SetJson()
+	}
+	return l, errSum
+}
+
+// List lists the path and returns the entries with their columns filled.
+// if the path is a directory, its entries are listed, unless WithDirectory is set
+func (l *Lister) List(path string) ([]*FileInfo, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*FileInfo, 0, 20)
+	if !stat.IsDir() || l.directory {
+		info, err := item.NewFileInfoWithOption(item.WithFileInfo(stat), item.WithAbsPath(abs))
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	} else {
+		d, err := os.ReadDir(abs)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range d {
+			fi, err := v.Info()
+			if err != nil {
+				continue
+			}
+			info, err := item.NewFileInfoWithOption(
+				item.WithFileInfo(fi), item.WithAbsPath(filepath.Join(abs, v.Name())),
+			)
+			if err != nil {
+				continue
+			}
+			infos = append(infos, info)
+		}
+	}
+	infos = l.itemFilter().Filter(infos...)
+
+	// the state of this call is kept in a copy, l is shared by the concurrent calls
+	call := *l
+	call.name = l.name.Clone()
+	call.size = l.size.Clone()
+	owner, group := *l.owner, *l.group
+	call.owner, call.group = &owner, &group
+	if l.git != nil {
+		repo := abs
+		if !stat.IsDir() {
+			repo = filepath.Dir(abs)
+		}
+		git := *l.git
+		git.Path = repo
+		git.InitCache(repo)
+		call.git = &git
+	}
+
+	cf := content.NewContentFilter(content.WithOptions(call.contentOptions()...))
+	cf.SetSortFunc(l.sortFunc())
+	cf.LimitN = l.limit
+	cf.GetDisplayItems(&infos)
+	return infos, nil
+}
+
+// Render lists the path and writes the result to w in the format set by WithFormat
+func (l *Lister) Render(w io.Writer, path string) error {
+	infos, err := l.List(path)
+	if err != nil {
+		return err
+	}
+	if len(infos) == 0 {
+		return nil
+	}
+
+	p := l.printer()
+	if rw, ok := p.(interface{ Reset(io.Writer) }); ok {
+		rw.Reset(w)
+	}
+	if _, ok := p.(*display.JsonPrinter); !ok {
+		display.AlignColumns(infos, infos[0].KeysByOrder(), make(map[string]int))
+	}
+	p.Print(infos...)
+	return nil
+}
+
+func (l *Lister) itemFilter() *filter.ItemFilter {
+	fs := make([]*FilterFunc, 0, len(l.filters)+1)
+	if !l.showHidden {
+		fs = append(fs, &filter.RemoveHidden)
+	}
+	fs = append(fs, l.filters...)
+	return filter.NewItemFilter(fs...)
+}
+
+func (l *Lister) renderer() *render.Renderer {
+	t := l.theme
+	if l.colorless || l.format == FormatJSON || l.format == FormatCSV || l.format == FormatTSV {
+		t = t.Classic()
+	}
+	return render.NewRenderer(t)
+}
+
+func (l *Lister) contentOptions() []content.ContentOption {
+	r := l.renderer()
+	opts := make([]content.ContentOption, 0, len(l.columns)+1)
+	for _, c := range l.columns {
+		opts = append(opts, c(l, r))
+	}
+	// name is always the last column
+	return append(opts, l.name.Enable(r))
+}
+
+func (l *Lister) sortFunc() SortFunc {
+	if l.noSort {
+		return nil
+	}
+	s := sorter.NewSorter(sorter.WithSortOption(l.sorts...))
+	if s.Len() == 0 {
+		s.AddOption(sorter.Default)
+	}
+	if l.reverse {
+		s.Reverse()
+	}
+	if l.dirFirst {
+		s.DirFirst()
+	}
+	return s.Build()
+}
+
+func (l *Lister) printer() display.Printer {
+	switch l.format {
+	case FormatAcross:
+		return display.NewAcross()
+	case FormatByline:
+		return display.NewByline()
+	case FormatComma:
+		return display.NewCommaPrint()
+	case FormatZero:
+		return display.NewZero()
+	case FormatJSON:
+		return display.NewJsonPrinter()
+	case FormatTable:
+		return display.NewTablePrinter(display.DefaultTB)
+	case FormatMarkdown:
+		return display.NewMDPrinter()
+	case FormatCSV:
+		return display.NewCSVPrinter()
+	case FormatTSV:
+		return display.NewTSVPrinter()
+	default:
+		return display.NewFitTerminal()
+	}
+}
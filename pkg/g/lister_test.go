@@ -0,0 +1,148 @@
+package g
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func prepareDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, size := range map[string]int{"a.txt": 1, "b.go": 20, ".hidden": 3} {
+		if err := os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func names(infos []*FileInfo) []string {
+	res := make([]string, 0, len(infos))
+	for _, info := range infos {
+		res = append(res, info.Name())
+	}
+	return res
+}
+
+func TestLister_List(t *testing.T) {
+	dir := prepareDir(t)
+
+	l, err := NewLister(WithSize(), WithoutColor(), WithSort(ByName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	infos, err := l.List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(names(infos), ","); got != "a.txt,b.go,sub" {
+		t.Errorf("List() = %s, want a.txt,b.go,sub", got)
+	}
+	for _, info := range infos {
+		if _, ok := info.Get("Size"); !ok {
+			t.Errorf("%s: Size column not set", info.Name())
+		}
+		if _, ok := info.Get("Name"); !ok {
+			t.Errorf("%s: Name column not set", info.Name())
+		}
+	}
+
+	l, _ = NewLister(WithShowHidden(), WithSort(BySizeDescend), WithLimit(2))
+	infos, err = l.List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("List() returns %d entries, want 2", len(infos))
+	}
+
+	l, _ = NewLister(WithMatch("*.go"))
+	infos, _ = l.List(dir)
+	if got := strings.Join(names(infos), ","); got != "b.go" {
+		t.Errorf("List() with WithMatch = %s, want b.go", got)
+	}
+
+	l, _ = NewLister(WithDirectory())
+	infos, _ = l.List(dir)
+	if len(infos) != 1 || infos[0].Name() != filepath.Base(dir) {
+		t.Errorf("List() with WithDirectory = %v, want %s", names(infos), filepath.Base(dir))
+	}
+
+	if _, err = l.List(filepath.Join(dir, "not-exist")); err == nil {
+		t.Error("List() on a nonexistent path should return an error")
+	}
+}
+
+func TestLister_Render(t *testing.T) {
+	dir := prepareDir(t)
+
+	l, err := NewLister(WithFormat(FormatByline), WithoutColor(), WithSort(ByName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err = l.Render(&buf, dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "a.txt\nb.go\nsub\n" {
+		t.Errorf("Render() = %q, want %q", got, "a.txt\nb.go\nsub\n")
+	}
+
+	l, _ = NewLister(WithFormat(FormatJSON), WithSize(), WithSort(ByName))
+	buf.Reset()
+	if err = l.Render(&buf, dir); err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Entries []map[string]string `json:"entries"`
+	}
+	if err = json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("Render() with FormatJSON is not valid json: %v\n%s", err, buf.String())
+	}
+	if len(out.Entries) != 3 || out.Entries[1]["name"] != "b.go" || out.Entries[1]["size"] == "" {
+		t.Errorf("Render() with FormatJSON = %v", out.Entries)
+	}
+}
+
+func TestNewLister_Error(t *testing.T) {
+	if _, err := NewLister(WithThemeFile("not-exist.json")); err == nil {
+		t.Error("NewLister() with a nonexistent theme file should return an error")
+	}
+}
+
+func TestLister_ListConcurrent(t *testing.T) {
+	dirs := []string{prepareDir(t), prepareDir(t)}
+	if err := os.WriteFile(filepath.Join(dirs[1], "c.md"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a.txt,b.go,sub", "a.txt,b.go,c.md,sub"}
+
+	l, err := NewLister(WithGit(), WithSize(), WithOwner(), WithGroup(), WithFormat(FormatJSON), WithSort(ByName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			infos, err := l.List(dirs[i%2])
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if got := strings.Join(names(infos), ","); got != want[i%2] {
+				t.Errorf("List() = %s, want %s", got, want[i%2])
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,290 @@
+package g
+
+import (
+	"github.com/Equationzhao/g/internal/content"
+	"github.com/Equationzhao/g/internal/filter"
+	"github.com/Equationzhao/g/internal/render"
+	"github.com/Equationzhao/g/internal/sorter"
+	"github.com/Equationzhao/g/internal/theme"
+)
+
+// Option configures a Lister, see NewLister
+type Option = func(l *Lister) error
+
+// WithFormat sets the format used by Lister.Render, default: FormatGrid
+func WithFormat(f Format) Option {
+	return func(l *Lister) error {
+		l.format = f
+		return nil
+	}
+}
+
+// WithTheme renders the columns with the given theme instead of the default one
+func WithTheme(t *Theme) Option {
+	return func(l *Lister) error {
+		l.theme = t
+		return nil
+	}
+}
+
+// WithThemeFile loads the theme at path, see docs/Theme.md for the format
+func WithThemeFile(path string) Option {
+	return func(l *Lister) error {
+		t, err := theme.ReadTheme(path)
+		if err != nil {
+			return err
+		}
+		l.theme = &t
+		return nil
+	}
+}
+
+// WithoutColor removes all colors and effects, icons are kept
+func WithoutColor() Option {
+	return func(l *Lister) error {
+		l.colorless = true
+		return nil
+	}
+}
+
+// WithShowHidden shows hidden files
+func WithShowHidden() Option {
+	return func(l *Lister) error {
+		l.showHidden = true
+		return nil
+	}
+}
+
+// WithDirectory lists directories themselves, not their contents
+func WithDirectory() Option {
+	return func(l *Lister) error {
+		l.directory = true
+		return nil
+	}
+}
+
+// WithLimit limits the result to a max of n entries, 0 means unlimited
+func WithLimit(n uint) Option {
+	return func(l *Lister) error {
+		l.limit = n
+		return nil
+	}
+}
+
+// WithFilter keeps the entries for which all the filters return true
+func WithFilter(f ...FilterFunc) Option {
+	return func(l *Lister) error {
+		for i := range f {
+			l.filters = append(l.filters, &f[i])
+		}
+		return nil
+	}
+}
+
+// WithMatch keeps the entries matching any of the glob patterns
+func WithMatch(globs ...string) Option {
+	return func(l *Lister) error {
+		f, err := filter.GlobOnly(globs...)
+		if err != nil {
+			return err
+		}
+		l.filters = append(l.filters, &f)
+		return nil
+	}
+}
+
+// WithIgnore removes the entries matching any of the glob patterns
+func WithIgnore(globs ...string) Option {
+	return func(l *Lister) error {
+		f, err := filter.RemoveGlob(globs...)
+		if err != nil {
+			return err
+		}
+		l.filters = append(l.filters, &f)
+		return nil
+	}
+}
+
+// WithSort sorts the entries by the sort functions in order,
+// the functions in package sorter of g can be used, default: natural order
+func WithSort(fn ...SortFunc) Option {
+	return func(l *Lister) error {
+		l.sorts = append(l.sorts, fn...)
+		return nil
+	}
+}
+
+// WithNoSort lists the entries in directory order
+func WithNoSort() Option {
+	return func(l *Lister) error {
+		l.noSort = true
+		return nil
+	}
+}
+
+// WithReverse reverses the order of the sort
+func WithReverse() Option {
+	return func(l *Lister) error {
+		l.reverse = true
+		return nil
+	}
+}
+
+// WithDirFirst lists directories before other files
+func WithDirFirst() Option {
+	return func(l *Lister) error {
+		l.dirFirst = true
+		return nil
+	}
+}
+
+// WithPermission adds the permission column, like -rwxr-xr-x
+func WithPermission() Option {
+	return withColumn(func(_ *Lister, r *render.Renderer) content.ContentOption {
+		return content.EnableFileMode(r)
+	})
+}
+
+// WithSize adds the size column
+func WithSize() Option {
+	return withColumn(func(l *Lister, r *render.Renderer) content.ContentOption {
+		return l.size.EnableSize(content.Auto, r)
+	})
+}
+
+// WithSI uses powers of 1000 not 1024 for the size column
+func WithSI() Option {
+	return func(l *Lister) error {
+		l.size.SetSI()
+		return nil
+	}
+}
+
+// WithOwner adds the owner column
+func WithOwner() Option {
+	return withColumn(func(l *Lister, r *render.Renderer) content.ContentOption {
+		return l.owner.EnableOwner(r)
+	})
+}
+
+// WithGroup adds the group column
+func WithGroup() Option {
+	return withColumn(func(l *Lister, r *render.Renderer) content.ContentOption {
+		return l.group.EnableGroup(r)
+	})
+}
+
+// WithNumeric lists numeric user and group IDs instead of names
+func WithNumeric() Option {
+	return func(l *Lister) error {
+		l.owner.EnableNumeric()
+		l.group.EnableNumeric()
+		return nil
+	}
+}
+
+// WithTimeFormat sets the layout of the time columns added after it, see time.Layout,
+// strftime format prefixed with '+' is also accepted
+func WithTimeFormat(layout string) Option {
+	return func(l *Lister) error {
+		l.timeFormat = layout
+		return nil
+	}
+}
+
+// WithTime adds a time column, mode is one of mod, create, access, birth
+func WithTime(mode string) Option {
+	return func(l *Lister) error {
+		format := l.timeFormat
+		l.columns = append(l.columns, func(_ *Lister, r *render.Renderer) content.ContentOption {
+			return content.EnableTime(format, mode, r)
+		})
+		return nil
+	}
+}
+
+// WithInode adds the inode column
+func WithInode() Option {
+	return withColumn(func(_ *Lister, r *render.Renderer) content.ContentOption {
+		return content.NewInodeEnabler().Enable(r)
+	})
+}
+
+// WithLinks adds the column of the number of hard links
+func WithLinks() Option {
+	return withColumn(func(_ *Lister, r *render.Renderer) content.ContentOption {
+		return content.NewLinkEnabler().Enable(r)
+	})
+}
+
+// WithGit adds the git status column
+func WithGit() Option {
+	return func(l *Lister) error {
+		l.git = content.NewGitEnabler()
+		l.columns = append(l.columns, func(l *Lister, r *render.Renderer) content.ContentOption {
+			return l.git.Enable(r)
+		})
+		return nil
+	}
+}
+
+// WithLong is like `g -l`: permission, size, owner, group and modified time
+func WithLong() Option {
+	return func(l *Lister) error {
+		for _, opt := range []Option{WithPermission(), WithSize(), WithOwner(), WithGroup(), WithTime("mod")} {
+			_ = opt(l)
+		}
+		return nil
+	}
+}
+
+// WithIcon shows icon before the name
+func WithIcon() Option {
+	return func(l *Lister) error {
+		l.name.SetIcon()
+		return nil
+	}
+}
+
+// WithClassify appends indicator (one of */=@|) to entries
+func WithClassify() Option {
+	return func(l *Lister) error {
+		l.name.SetClassify()
+		return nil
+	}
+}
+
+// WithFullPath shows the full path instead of the name
+func WithFullPath() Option {
+	return func(l *Lister) error {
+		l.name.SetFullPath()
+		return nil
+	}
+}
+
+// WithRelativeTo shows the path relative to the given directory instead of the name
+func WithRelativeTo(dir string) Option {
+	return func(l *Lister) error {
+		l.name.SetRelativeTo(dir)
+		return nil
+	}
+}
+
+func withColumn(c column) Option {
+	return func(l *Lister) error {
+		l.columns = append(l.columns, c)
+		return nil
+	}
+}
+
+// the sort functions of g, for WithSort
+var (
+	ByName        SortFunc = sorter.ByNameAscend
+	ByNameDescend SortFunc = sorter.ByNameDescend
+	BySize        SortFunc = sorter.BySizeAscend
+	BySizeDescend SortFunc = sorter.BySizeDescend
+	ByExtension   SortFunc = sorter.ByExtensionAscend
+	ByVersion     SortFunc = sorter.ByVersionAscend
+	ByModTime              = sorter.ByTimeAscend("mod")
+	Natural       SortFunc = sorter.Default
+)